@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/s3nn1k/ef-mob-task/internal/config"
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/internal/service"
+	"github.com/s3nn1k/ef-mob-task/internal/storage/postgres"
+)
+
+// runKeys runs keys subcommand with the given args
+func runKeys(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: keys issue --name <name> --scope read|write|admin | keys list | keys revoke --id <id>")
+	}
+
+	db, err := postgres.ConnectDB(cfg.DB.ConnString())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	srvc := service.NewKeyService(postgres.NewKeyStorage(db))
+
+	ctx := cliContext(cfg)
+
+	switch args[0] {
+	case "issue":
+		return issueKey(ctx, srvc, args[1:])
+	case "list":
+		return listKeys(ctx, srvc)
+	case "revoke":
+		return revokeKey(ctx, srvc, args[1:])
+	default:
+		return fmt.Errorf("unknown keys command: %s", args[0])
+	}
+}
+
+// issueKey creates API key and prints it's secret
+func issueKey(ctx context.Context, srvc service.KeyServiceIface, args []string) error {
+	fs := flag.NewFlagSet("keys issue", flag.ContinueOnError)
+
+	name := fs.String("name", "", "name of key owner")
+	scope := fs.String("scope", models.ScopeRead, "scope of key: read, write or admin")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *name == "" {
+		return errors.New("--name must be set")
+	}
+
+	key, secret, err := srvc.Issue(ctx, *name, *scope)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("id: %d, name: %s, scope: %s\n", key.Id, key.Name, key.Scope)
+	fmt.Printf("key: %s\n", secret)
+	fmt.Println("Save the key now, it can't be shown again")
+
+	return nil
+}
+
+// listKeys prints all API keys without secrets
+func listKeys(ctx context.Context, srvc service.KeyServiceIface) error {
+	keys, err := srvc.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "ID\tNAME\tPREFIX\tSCOPE\tCREATED\tREVOKED")
+
+	for _, key := range keys {
+		revoked := "-"
+		if key.Revoked != nil {
+			revoked = key.Revoked.Format(time.RFC3339)
+		}
+
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n", key.Id, key.Name, key.Prefix, key.Scope, key.Created.Format(time.RFC3339), revoked)
+	}
+
+	return tw.Flush()
+}
+
+// revokeKey revokes API key by id
+func revokeKey(ctx context.Context, srvc service.KeyServiceIface, args []string) error {
+	fs := flag.NewFlagSet("keys revoke", flag.ContinueOnError)
+
+	id := fs.Int("id", 0, "id of key")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ok, err := srvc.Revoke(ctx, *id)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("key %d not exists or already revoked", *id)
+	}
+
+	fmt.Printf("key %d revoked\n", *id)
+
+	return nil
+}
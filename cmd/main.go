@@ -2,20 +2,18 @@ package main
 
 import (
 	"errors"
+	"io/fs"
 	"log"
-	"net/http"
+	"log/slog"
 	"os"
-	"os/signal"
-	"syscall"
 
 	"github.com/joho/godotenv"
-	"github.com/s3nn1k/ef-mob-task/internal/app"
-	"github.com/s3nn1k/ef-mob-task/internal/client/dummy"
 	"github.com/s3nn1k/ef-mob-task/internal/config"
 )
 
+// init loads .env file if it exists, config could also be set by file, environment and flags
 func init() {
-	if err := godotenv.Load(); err != nil {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
 		log.Fatalf("[ERROR] Can't load environment: %s", err.Error())
 	}
 }
@@ -25,41 +23,58 @@ func init() {
 // @description API for managing a song library
 // @contact.url https://github.com/s3nn1k
 // @BasePath /
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description JWT or API key with Bearer prefix
 func main() {
-	cfg, err := config.LoadFromEnv()
+	cfg, args, err := config.Load(os.Args[1:])
 	if err != nil {
 		log.Fatalf("[ERROR] Can't load config: %s", err.Error())
 	}
 
-	app, err := app.New(cfg)
-	if err != nil {
-		log.Fatalf("[ERROR] Can't create app: %s", err.Error())
+	// Server is started if command is not set
+	cmd := "serve"
+	if len(args) > 0 {
+		cmd, args = args[0], args[1:]
+	}
+
+	commands := map[string]func(*config.Config, []string) error{
+		"serve":     runServe,
+		"migrate":   runMigrate,
+		"songs":     runSongs,
+		"keys":      runKeys,
+		"dummy-api": runDummyApi,
+		"config":    runConfig,
 	}
 
-	dummy := dummy.New(cfg.API)
+	run, ok := commands[cmd]
+	if !ok {
+		log.Fatalf("[ERROR] Unknown command: %s\n%s", cmd, usage)
+	}
 
-	if cfg.UseTestApi {
-		go func() {
-			_ = dummy.Run()
-		}()
+	if err := run(cfg, args); err != nil {
+		log.Fatalf("[ERROR] %s", err.Error())
 	}
+}
 
-	go func() {
-		if err := app.Run(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			log.Println(err)
-		}
-	}()
+// usage describes available commands
+const usage = `usage: app [config flags] <command> [args]
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+commands:
+  serve                                   run API server, default command
+  migrate up|down|status|goto             manage database schema
+  songs list|get|delete|import|export     manage songs in storage
+  keys issue|list|revoke                  manage API keys
+  dummy-api                               run test API standalone
+  config                                  print effective config with hidden secrets`
 
-	err = app.Stop()
-	if err != nil {
-		log.Println(err)
-	}
+// runConfig prints effective config with hidden secrets
+func runConfig(cfg *config.Config, args []string) error {
+	slog.New(slog.NewTextHandler(os.Stdout, nil)).Info("Effective config", "config", cfg.LogValue())
 
-	if cfg.UseTestApi {
-		_ = dummy.Stop()
-	}
+	return nil
 }
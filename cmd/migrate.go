@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/s3nn1k/ef-mob-task/internal/config"
+	"github.com/s3nn1k/ef-mob-task/internal/migrator"
+)
+
+// runMigrate runs migrate subcommand with the given args
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: migrate up | migrate down [--steps N] | migrate status | migrate goto <version>")
+	}
+
+	m, err := migrator.New(cfg.DB.Migrations, cfg.DB.ConnString())
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	switch args[0] {
+	case "up":
+		if err := m.Up(); err != nil {
+			return err
+		}
+	case "down":
+		fs := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+
+		steps := fs.Int("steps", 1, "count of migrations to roll back")
+
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		if err := m.Down(*steps); err != nil {
+			return err
+		}
+	case "goto":
+		if len(args) != 2 {
+			return errors.New("usage: migrate goto <version>")
+		}
+
+		version, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+
+		if err := m.Goto(uint(version)); err != nil {
+			return err
+		}
+	case "status":
+	default:
+		return fmt.Errorf("unknown migrate command: %s", args[0])
+	}
+
+	return printStatus(m)
+}
+
+// printStatus prints applied and latest versions of schema
+func printStatus(m *migrator.Migrator) error {
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("version: %d, latest: %d, dirty: %t\n", status.Version, status.Latest, status.Dirty)
+
+	if status.Version < status.Latest {
+		fmt.Println("there are not applied migrations, run migrate up")
+	}
+
+	return nil
+}
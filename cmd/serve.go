@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/joho/godotenv"
+	"github.com/s3nn1k/ef-mob-task/internal/app"
+	"github.com/s3nn1k/ef-mob-task/internal/client/dummy"
+	"github.com/s3nn1k/ef-mob-task/internal/config"
+	"github.com/s3nn1k/ef-mob-task/internal/lifecycle"
+)
+
+// runServe runs API server until SIGINT or SIGTERM
+// Test API is started with server if it's enabled by config
+func runServe(cfg *config.Config, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("unexpected arguments of serve: %v", args)
+	}
+
+	app, err := app.New(cfg)
+	if err != nil {
+		return fmt.Errorf("can't create app: %w", err)
+	}
+
+	if cfg.UseTestApi {
+		dummy := dummy.New(cfg.API)
+
+		go func() {
+			_ = dummy.Run()
+		}()
+
+		// Test API is upstream of the app, so it's stopped after in-flight requests and workers that call it
+		app.OnStop(lifecycle.PhaseResources, "test api", dummy.Stop)
+	}
+
+	go func() {
+		if err := app.Run(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Println(err)
+		}
+	}()
+
+	// SIGHUP reloads config and applies log level without restart
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+
+	go func() {
+		for range reload {
+			if err := godotenv.Overload(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				log.Printf("[ERROR] Can't reload environment: %s", err.Error())
+				continue
+			}
+
+			cfg, _, err := config.Load(os.Args[1:])
+			if err != nil {
+				log.Printf("[ERROR] Can't reload config: %s", err.Error())
+				continue
+			}
+
+			if err := app.SetLevel(cfg.Level); err != nil {
+				log.Printf("[ERROR] Can't change log level: %s", err.Error())
+			}
+		}
+	}()
+
+	waitSignal()
+
+	signal.Stop(reload)
+
+	return app.Stop()
+}
+
+// runDummyApi runs test API standalone until SIGINT or SIGTERM
+func runDummyApi(cfg *config.Config, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("unexpected arguments of dummy-api: %v", args)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	dummy := dummy.New(cfg.API)
+
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- dummy.Run()
+	}()
+
+	log.Printf("Test API is listening on %s:%s", cfg.API.Host, cfg.API.Port)
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	return dummy.Stop(ctx)
+}
+
+// waitSignal blocks until SIGINT or SIGTERM
+func waitSignal() {
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	signal.Stop(quit)
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/s3nn1k/ef-mob-task/internal/app"
+	"github.com/s3nn1k/ef-mob-task/internal/config"
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/internal/service"
+	"github.com/s3nn1k/ef-mob-task/internal/storage"
+	"github.com/s3nn1k/ef-mob-task/internal/storage/postgres"
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+)
+
+// runSongs runs songs subcommand with the given args
+func runSongs(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: songs list [--song --group --date --limit --offset] | songs get --id <id> | songs delete --id <id> | songs import|export --file <path> [--format csv|jsonl]")
+	}
+
+	switch args[0] {
+	case "list":
+		return listSongs(cfg, args[1:])
+	case "get":
+		return getSong(cfg, args[1:])
+	case "delete":
+		return deleteSong(cfg, args[1:])
+	case "import":
+		return runImport(cfg, args[1:])
+	case "export":
+		return runExport(cfg, args[1:])
+	default:
+		return fmt.Errorf("unknown songs command: %s", args[0])
+	}
+}
+
+// listSongs prints songs that match filters without texts
+func listSongs(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("songs list", flag.ContinueOnError)
+
+	var filters models.GetFilters
+
+	fs.StringVar(&filters.Song, "song", "", "list songs with title")
+	fs.StringVar(&filters.Group, "group", "", "list songs of group")
+	fs.StringVar(&filters.Date, "date", "", "list songs with release date in format 02.01.2006")
+	fs.IntVar(&filters.Limit, "limit", 20, "count of songs")
+	fs.IntVar(&filters.Offset, "offset", 0, "count of skipped songs")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	strg, closeDB, err := newStorage(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	songs, err := strg.GetAll(cliContext(cfg), filters)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "ID\tSONG\tGROUP\tRELEASE\tLINK")
+
+	for _, song := range songs {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", song.Id, song.Song, song.Group, song.Date, song.Link)
+	}
+
+	return tw.Flush()
+}
+
+// getSong prints song with text by id
+func getSong(cfg *config.Config, args []string) error {
+	id, err := parseId("songs get", args)
+	if err != nil {
+		return err
+	}
+
+	strg, closeDB, err := newStorage(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	songs, err := strg.GetAll(cliContext(cfg), models.GetFilters{Id: id, Limit: 1})
+	if err != nil {
+		return err
+	}
+
+	if len(songs) == 0 {
+		return fmt.Errorf("song %d not exists", id)
+	}
+
+	song := songs[0]
+
+	fmt.Printf("id: %d\nsong: %s\ngroup: %s\nrelease: %s\nlink: %s\n\n%s\n", song.Id, song.Song, song.Group, song.Date, song.Link, song.Text)
+
+	return nil
+}
+
+// deleteSong deletes song by id
+func deleteSong(cfg *config.Config, args []string) error {
+	id, err := parseId("songs delete", args)
+	if err != nil {
+		return err
+	}
+
+	strg, closeDB, err := newStorage(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	ok, err := strg.Delete(cliContext(cfg), id)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("song %d not exists", id)
+	}
+
+	fmt.Printf("song %d deleted\n", id)
+
+	return nil
+}
+
+// parseId parses required --id flag of command
+func parseId(name string, args []string) (int, error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+
+	id := fs.Int("id", 0, "id of song")
+
+	if err := fs.Parse(args); err != nil {
+		return 0, err
+	}
+
+	if *id < 1 {
+		return 0, errors.New("--id must be set")
+	}
+
+	return *id, nil
+}
+
+// runImport imports songs from csv or jsonl file and prints report
+// Format is detected by file extension if it isn't set
+func runImport(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("songs import", flag.ContinueOnError)
+
+	file := fs.String("file", "", "path to csv or jsonl file")
+	format := fs.String("format", "", "format of file: csv or jsonl, detected by extension if empty")
+	enrich := fs.Bool("enrich", false, "get missing text, link and release date from API")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *file == "" {
+		return errors.New("--file must be set")
+	}
+
+	if *format == "" {
+		*format = detectFormat(*file)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	srvc, closeDB, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	ctx := cliContext(cfg)
+
+	report, err := srvc.Import(ctx, f, models.ImportOptions{Format: *format, Enrich: *enrich})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("total: %d, created: %d, failed: %d\n", report.Total, report.Created, report.Failed)
+
+	for _, rowErr := range report.Errors {
+		fmt.Printf("row %d: %s\n", rowErr.Row, rowErr.Error)
+	}
+
+	return nil
+}
+
+// runExport exports songs that match filters into file or stdout
+// Format is detected by file extension if it isn't set
+func runExport(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("songs export", flag.ContinueOnError)
+
+	file := fs.String("file", "", "path to output csv or jsonl file, stdout if empty")
+	format := fs.String("format", "", "format of file: csv or jsonl, detected by extension if empty")
+
+	var filters models.GetFilters
+
+	fs.StringVar(&filters.Song, "song", "", "export songs with title")
+	fs.StringVar(&filters.Group, "group", "", "export songs of group")
+	fs.StringVar(&filters.Date, "date", "", "export songs with release date in format 02.01.2006")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *format == "" {
+		*format = detectFormat(*file)
+	}
+
+	if *format != models.FormatCsv && *format != models.FormatJsonl {
+		return errors.New("can't detect format, set --format to csv or jsonl")
+	}
+
+	srvc, closeDB, err := newService(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	out := os.Stdout
+
+	if *file != "" {
+		f, err := os.Create(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		out = f
+	}
+
+	w := bufio.NewWriter(out)
+
+	if err := srvc.Export(cliContext(cfg), w, *format, filters); err != nil {
+		return err
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if *file != "" {
+		return out.Sync()
+	}
+
+	return nil
+}
+
+// newStorage connects to database and creates storage of songs
+// Returns func that closes database connection
+func newStorage(cfg *config.Config) (storage.Storage, func(), error) {
+	db, err := postgres.ConnectDB(cfg.DB.ConnString())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return postgres.NewStorage(db), db.Close, nil
+}
+
+// cliContext returns context with logger of commands, logs are written into stderr to keep output clean
+func cliContext(cfg *config.Config) context.Context {
+	level, _ := logger.ParseLevel(cfg.Level)
+
+	return logger.NewCtxWithLog(context.Background(), slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
+}
+
+// newService connects to database and creates service
+// Returns func that closes database connection
+func newService(cfg *config.Config) (service.ServiceIface, func(), error) {
+	db, err := postgres.ConnectDB(cfg.DB.ConnString())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	clnt, err := app.NewAPIClient(cfg.API)
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+
+	srvc := service.New(postgres.NewStorage(db), clnt, cfg.API.Concurrency)
+
+	return srvc, db.Close, nil
+}
+
+// detectFormat returns format of file by it's extension
+func detectFormat(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".csv":
+		return models.FormatCsv
+	case ".jsonl", ".ndjson":
+		return models.FormatJsonl
+	default:
+		return ""
+	}
+}
@@ -2,85 +2,246 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"net"
 	"net/http"
+	"os"
 
-	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
-	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/s3nn1k/ef-mob-task/docs"
-	"github.com/s3nn1k/ef-mob-task/internal/client"
+	jwtauth "github.com/s3nn1k/ef-mob-task/internal/auth"
+	"github.com/s3nn1k/ef-mob-task/internal/certs"
 	"github.com/s3nn1k/ef-mob-task/internal/config"
 	"github.com/s3nn1k/ef-mob-task/internal/delivery"
+	"github.com/s3nn1k/ef-mob-task/internal/delivery/graph"
 	"github.com/s3nn1k/ef-mob-task/internal/delivery/middleware"
+	"github.com/s3nn1k/ef-mob-task/internal/delivery/rpc"
+	"github.com/s3nn1k/ef-mob-task/internal/lifecycle"
+	"github.com/s3nn1k/ef-mob-task/internal/metrics"
+	"github.com/s3nn1k/ef-mob-task/internal/migrator"
+	"github.com/s3nn1k/ef-mob-task/internal/models"
 	"github.com/s3nn1k/ef-mob-task/internal/service"
 	"github.com/s3nn1k/ef-mob-task/internal/storage/postgres"
+	"github.com/s3nn1k/ef-mob-task/internal/tracing"
 	"github.com/s3nn1k/ef-mob-task/pkg/logger"
 	httpSwagger "github.com/swaggo/http-swagger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 type App struct {
-	db     *pgxpool.Pool
 	server *http.Server
+	// grpc is nil if gRPC server is disabled
+	grpc   *grpc.Server
+	grpcLn net.Listener
+	log    *slog.Logger
+	level  *slog.LevelVar
+	lc     *lifecycle.Lifecycle
 }
 
+// Run listens https if server has TLS config, certificate is taken from it
+// gRPC server is served in background with the same TLS config
 func (a *App) Run() error {
+	if a.grpc != nil {
+		go func() {
+			if err := a.grpc.Serve(a.grpcLn); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+				a.log.Error("gRPC server failed: " + err.Error())
+			}
+		}()
+	}
+
+	if a.server.TLSConfig != nil {
+		return a.server.ListenAndServeTLS("", "")
+	}
+
 	return a.server.ListenAndServe()
 }
 
+// Stop stops accepting requests, drains in-flight ones, stops workers and then closes resources
+// Every phase is limited by shutdown timeout, errors of all components are returned at once
 func (a *App) Stop() error {
-	a.db.Close()
+	return a.lc.Stop(context.Background())
+}
 
-	err := a.server.Shutdown(context.Background())
+// OnStop registers component, that is stopped with app in the given phase
+func (a *App) OnStop(phase lifecycle.Phase, name string, stop func(ctx context.Context) error) {
+	a.lc.OnStop(phase, name, stop)
+}
+
+// SetLevel changes logging level without restart
+func (a *App) SetLevel(name string) error {
+	level, err := logger.ParseLevel(name)
 	if err != nil {
 		return err
 	}
 
+	a.level.Set(level)
+
+	a.log.Warn("Changed log level", slog.String("to", logger.LevelName(level)))
+
 	return nil
 }
 
 // New creates new instance of application, sets the dependencies and applies migrations
-func New(cfg *config.Config) (*App, error) {
-	log := logger.NewTextLogger(cfg.Level)
+// Already created components are stopped if app can't be created
+func New(cfg *config.Config) (a *App, err error) {
+	level := new(slog.LevelVar)
+
+	lvl, err := logger.ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	level.Set(lvl)
+
+	var (
+		out     io.Writer = os.Stdout
+		logFile io.WriteCloser
+	)
+
+	if cfg.Log.File != "" {
+		logFile = logger.NewFileWriter(cfg.Log.File, cfg.Log.MaxSize, cfg.Log.MaxBackups, cfg.Log.MaxAge)
+		out = logFile
+	}
+
+	log := logger.New(out, cfg.Log.Format, level)
+
+	if cfg.Log.SampleFirst > 0 {
+		log = slog.New(logger.NewSamplingHandler(log.Handler(), cfg.Log.SampleFirst, cfg.Log.SampleThereafter))
+	}
+
+	logger.SetPolicy(logger.Policy{
+		MaxFieldLen: cfg.Log.MaxFieldLen,
+		MaxItems:    cfg.Log.MaxItems,
+		RedactText:  cfg.Log.RedactText,
+	})
+
+	log.Info("Created logger", slog.String("level", logger.LevelName(lvl)), "config", cfg.Log.LogValue())
 
-	log.Info("Created logger", slog.String("level", cfg.Level))
+	lc := lifecycle.New(log, cfg.Server.ShutdownTimeout)
 
-	connStr := fmt.Sprintf("postgresql://%s:%s@%s:%s/%s?sslmode=disable", cfg.DB.User, cfg.DB.Pass, cfg.DB.Host, cfg.DB.Port, cfg.DB.Name)
+	defer func() {
+		if err != nil {
+			lc.Stop(context.Background())
+		}
+	}()
 
-	m, err := migrate.New("file:///migrations", connStr)
+	// Log file is closed last, because it's registered first
+	if logFile != nil {
+		lc.OnStop(lifecycle.PhaseResources, "log file", func(ctx context.Context) error {
+			return logFile.Close()
+		})
+	}
+
+	log.Debug("Loaded config", "config", cfg.LogValue())
+
+	shutdown, err := tracing.Setup(context.Background(), cfg.Tracing)
 	if err != nil {
 		return nil, err
 	}
 
-	if err = m.Up(); err != nil {
+	// Remaining spans are flushed after the last request
+	lc.OnStop(lifecycle.PhaseResources, "tracing", shutdown)
+
+	log.Info("Setup tracing", "config", cfg.Tracing.LogValue())
+
+	connStr := cfg.DB.ConnString()
+
+	db, err := postgres.ConnectDB(connStr)
+	if err != nil {
 		return nil, err
 	}
 
-	db, err := postgres.ConnectDB(connStr)
+	lc.OnStop(lifecycle.PhaseResources, "postgres", func(ctx context.Context) error {
+		db.Close()
+		return nil
+	})
+
+	// Readiness fails if schema becomes dirty or older than the one that app was started with
+	schemaVersion, err := migrateSchema(db, connStr, cfg.DB, log)
 	if err != nil {
 		return nil, err
 	}
 
-	log.Info("Connected to database and applied migrations", "config", cfg.DB.AsLogValue())
+	log.Info("Connected to database", "config", cfg.DB.LogValue())
 
 	strg := postgres.NewStorage(db)
 
-	clnt := client.New(cfg.API.Host, cfg.API.Port)
+	mtrcs := metrics.New()
+	mtrcs.RegisterPool(db)
+	mtrcs.RegisterSongs(strg.Count)
+
+	apiClnt, err := NewAPIClient(cfg.API)
+	if err != nil {
+		return nil, err
+	}
 
-	log.Info("Setup API client", "config", cfg.API.AsLogValue())
+	clnt := mtrcs.InstrumentClient(apiClnt)
 
-	srvc := service.New(strg, clnt)
+	log.Info("Setup API client", "config", cfg.API.LogValue())
+
+	srvc := service.New(strg, clnt, cfg.API.Concurrency)
 
 	hndlr := delivery.NewHandler(log, srvc)
 
+	gqlHndlr, err := graph.NewHandler(log, srvc)
+	if err != nil {
+		return nil, err
+	}
+
+	plstHndlr := delivery.NewPlaylistHandler(log, service.NewPlaylistService(postgres.NewPlaylistStorage(db)))
+
 	addr := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
 
-	r := initRoutes(hndlr, log)
+	var auth *middleware.Auth
+	if cfg.Server.Auth {
+		var tokens middleware.TokenVerifier
+
+		if cfg.JWT.Enabled() {
+			verifier, err := jwtauth.NewJWTVerifier(cfg.JWT)
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = verifier
+
+			log.Info("Loaded jwt keys", "config", cfg.JWT.LogValue())
+		}
+
+		auth = middleware.NewAuth(log, service.NewKeyService(postgres.NewKeyStorage(db)), tokens)
+	}
+
+	limiter := middleware.NewRateLimiter(cfg.RateLimit)
+
+	log.Info("Setup rate limits", "config", cfg.RateLimit.LogValue())
+
+	health := delivery.NewHealthHandler(log)
+
+	health.AddCheck("postgres", db.Ping)
+	health.AddCheck("migrations", func(ctx context.Context) error {
+		version, dirty, err := postgres.SchemaVersion(ctx, db)
+		if err != nil {
+			return err
+		}
+
+		// Schema is migrated by newer replicas during rolling update, so newer version doesn't fail readiness
+		return migrator.Status{Version: version, Latest: schemaVersion, Dirty: dirty}.CheckServing()
+	})
+
+	if cfg.API.HealthCheck {
+		health.AddCheck("api", apiClnt.Ping)
+	}
+
+	lvlHndlr := delivery.NewLogLevelHandler(log, level)
+
+	r := initRoutes(hndlr, plstHndlr, gqlHndlr, health, lvlHndlr, auth, limiter, mtrcs, log)
 
 	app := &App{
-		db: db,
+		log:   log,
+		level: level,
+		lc:    lc,
 		server: &http.Server{
 			Addr:           addr,
 			MaxHeaderBytes: 1 << 20,
@@ -91,33 +252,176 @@ func New(cfg *config.Config) (*App, error) {
 		},
 	}
 
-	log.Info("Created app with server", "config", cfg.Server.AsLogValue())
+	if cfg.Server.TLSEnabled() {
+		reloader, err := certs.NewReloader(log, cfg.Server.TLSCert, cfg.Server.TLSKey)
+		if err != nil {
+			return nil, err
+		}
+
+		app.server.TLSConfig, err = certs.ServerConfig(reloader, cfg.Server.TLSClientCA, cfg.Server.TLSClientAuth)
+		if err != nil {
+			return nil, err
+		}
+
+		// Renewed certificate is applied to new connections without restart
+		lc.Go("tls reload", reloader.Watch)
+
+		log.Info("Loaded tls certificate", slog.String("cert", cfg.Server.TLSCert))
+	}
+
+	// Shutdown stops accepting connections and waits for in-flight requests
+	lc.OnStop(lifecycle.PhaseServers, "http server", app.server.Shutdown)
+
+	if cfg.Server.GRPCEnabled {
+		var opts []grpc.ServerOption
+		if app.server.TLSConfig != nil {
+			opts = append(opts, grpc.Creds(credentials.NewTLS(app.server.TLSConfig)))
+		}
+
+		app.grpc = rpc.NewGRPCServer(log, rpc.NewServer(log, srvc), auth, limiter, opts...)
+
+		app.grpcLn, err = net.Listen("tcp", fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.GRPCPort))
+		if err != nil {
+			return nil, err
+		}
+
+		lc.OnStop(lifecycle.PhaseServers, "grpc server", func(ctx context.Context) error {
+			return stopGRPC(ctx, app.grpc)
+		})
+
+		log.Info("Created gRPC server", slog.String("port", cfg.Server.GRPCPort))
+	}
+
+	// Readiness fails first and servers keep accepting connections for drain delay,
+	// so balancer stops routing new requests to the app before listeners are closed
+	lc.OnStop(lifecycle.PhaseServers, "readiness", func(ctx context.Context) error {
+		return health.Drain(ctx, cfg.Server.DrainDelay)
+	})
+
+	log.Info("Created app with server", "config", cfg.Server.LogValue())
 
 	log.Info("Is test api service in use", slog.Bool("value", cfg.UseTestApi))
 
-	log.Info("Visit " + fmt.Sprintf("http://localhost:%s/swagger/index.html", cfg.Server.Port) + " to test the API")
+	scheme := "http"
+	if cfg.Server.TLSEnabled() {
+		scheme = "https"
+	}
+
+	log.Info("Visit " + fmt.Sprintf("%s://localhost:%s/swagger/index.html", scheme, cfg.Server.Port) + " to test the API")
 
 	return app, nil
 }
 
-func initRoutes(h *delivery.Handler, log *slog.Logger) *http.ServeMux {
+// initRoutes registers routes with required roles, that are the same as scopes of API keys, and rate limits
+// Routes are not protected if auth is nil. Requests are limited by remote address with loose address limit before auth,
+// so guessing of credentials is limited too, and by principal with limit of route after it
+func initRoutes(h *delivery.Handler, p *delivery.PlaylistHandler, gql *graph.Handler, health *delivery.HealthHandler, lvl *delivery.LogLevelHandler, auth *middleware.Auth, limiter *middleware.RateLimiter, mtrcs *metrics.Metrics, log *slog.Logger) *http.ServeMux {
 	router := http.NewServeMux()
 
-	router.Handle("POST /songs", middleware.WithLogging(log, http.HandlerFunc(h.Create)))
-	router.Handle("PUT /songs/{id}", middleware.WithLogging(log, http.HandlerFunc(h.Update)))
-	router.Handle("GET /songs", middleware.WithLogging(log, http.HandlerFunc(h.GetAll)))
-	router.Handle("GET /songs/{id}", middleware.WithLogging(log, http.HandlerFunc(h.GetVerses)))
-	router.Handle("DELETE /songs/{id}", middleware.WithLogging(log, http.HandlerFunc(h.Delete)))
+	handle := func(pattern string, role string, next http.HandlerFunc) {
+		handler := limiter.Limit(pattern, next)
+
+		if auth != nil {
+			handler = limiter.LimitAddr(auth.Require(role, handler))
+		}
+
+		handler = middleware.WithMetrics(mtrcs, pattern, handler)
+
+		router.Handle(pattern, middleware.WithRequestId(middleware.WithLogging(log, middleware.WithTracing(pattern, handler))))
+	}
+
+	handle("POST /songs", models.ScopeWrite, h.Create)
+	handle("POST /songs:batch", models.ScopeWrite, h.CreateBatch)
+	handle("DELETE /songs:batch", models.ScopeAdmin, h.DeleteBatch)
+	handle("POST /songs/import", models.ScopeAdmin, h.Import)
+	handle("GET /songs/export", models.ScopeRead, h.Export)
+	handle("PUT /songs/{id}", models.ScopeWrite, h.Update)
+	handle("GET /songs", models.ScopeRead, h.GetAll)
+	handle("GET /songs/{id}", models.ScopeRead, h.GetVerses)
+	handle("GET /songs/{id}/sections", models.ScopeRead, h.GetSections)
+	handle("PUT /songs/{id}/lyrics", models.ScopeWrite, h.SetLyrics)
+	handle("GET /songs/{id}/lyrics", models.ScopeRead, h.GetLyrics)
+	handle("GET /songs/{id}/translations", models.ScopeRead, h.GetTranslations)
+	handle("PUT /songs/{id}/translations/{lang}", models.ScopeWrite, h.SetTranslation)
+	handle("GET /songs/{id}/translations/{lang}", models.ScopeRead, h.GetTranslation)
+	handle("DELETE /songs/{id}/translations/{lang}", models.ScopeWrite, h.DeleteTranslation)
+	handle("DELETE /songs/{id}", models.ScopeWrite, h.Delete)
+
+	handle("POST /playlists", models.ScopeWrite, p.Create)
+	handle("GET /playlists/{id}", models.ScopeRead, p.Get)
+	handle("DELETE /playlists/{id}", models.ScopeWrite, p.Delete)
+	handle("POST /playlists/{id}/songs", models.ScopeWrite, p.AddSong)
+	handle("PUT /playlists/{id}/songs/{position}", models.ScopeWrite, p.MoveSong)
+	handle("DELETE /playlists/{id}/songs/{position}", models.ScopeWrite, p.RemoveSong)
+
+	// Mutations check write role by themselves
+	handle("POST /graphql", models.ScopeRead, gql.Query)
+
+	handle("GET /admin/log-level", models.ScopeAdmin, lvl.Get)
+	handle("PUT /admin/log-level", models.ScopeAdmin, lvl.Set)
 
 	router.Handle("GET /swagger/", httpSwagger.WrapHandler)
 
+	// Metrics require admin role, scraper sends key as bearer token. Scrapes are not logged and not counted
+	metricsHandler := mtrcs.Handler()
+	if auth != nil {
+		metricsHandler = limiter.LimitAddr(auth.Require(models.ScopeAdmin, metricsHandler))
+	}
+
+	router.Handle("GET /metrics", metricsHandler)
+
+	// Probes are not protected and not limited, so orchestrator can always reach them
+	router.HandleFunc("GET /healthz", health.Live)
+	router.HandleFunc("GET /readyz", health.Ready)
+
 	log.Info("Available routes", slog.Group("route",
 		slog.String("Create", "POST /songs"),
+		slog.String("CreateBatch", "POST /songs:batch"),
+		slog.String("DeleteBatch", "DELETE /songs:batch"),
+		slog.String("Import", "POST /songs/import"),
+		slog.String("Export", "GET /songs/export"),
 		slog.String("Update", "PUT /songs/{id}"),
 		slog.String("GetAll", "GET /songs"),
 		slog.String("GetVerses", "GET /songs/{id}"),
+		slog.String("GetSections", "GET /songs/{id}/sections"),
+		slog.String("SetLyrics", "PUT /songs/{id}/lyrics"),
+		slog.String("GetLyrics", "GET /songs/{id}/lyrics"),
+		slog.String("GetTranslations", "GET /songs/{id}/translations"),
+		slog.String("SetTranslation", "PUT /songs/{id}/translations/{lang}"),
+		slog.String("GetTranslation", "GET /songs/{id}/translations/{lang}"),
+		slog.String("DeleteTranslation", "DELETE /songs/{id}/translations/{lang}"),
 		slog.String("Delete", "DELETE /songs/{id}"),
-		slog.String("Swagger", "GET /swagger/")))
+		slog.String("CreatePlaylist", "POST /playlists"),
+		slog.String("GetPlaylist", "GET /playlists/{id}"),
+		slog.String("DeletePlaylist", "DELETE /playlists/{id}"),
+		slog.String("AddPlaylistSong", "POST /playlists/{id}/songs"),
+		slog.String("MovePlaylistSong", "PUT /playlists/{id}/songs/{position}"),
+		slog.String("RemovePlaylistSong", "DELETE /playlists/{id}/songs/{position}"),
+		slog.String("GraphQL", "POST /graphql"),
+		slog.String("GetLogLevel", "GET /admin/log-level"),
+		slog.String("SetLogLevel", "PUT /admin/log-level"),
+		slog.String("Swagger", "GET /swagger/"),
+		slog.String("Metrics", "GET /metrics"),
+		slog.String("Liveness", "GET /healthz"),
+		slog.String("Readiness", "GET /readyz")))
 
 	return router
 }
+
+// stopGRPC waits for in-flight calls, they are cancelled if context is done first
+func stopGRPC(ctx context.Context, server *grpc.Server) error {
+	done := make(chan struct{})
+
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		server.Stop()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,21 @@
+package app
+
+import (
+	"github.com/s3nn1k/ef-mob-task/internal/certs"
+	"github.com/s3nn1k/ef-mob-task/internal/client"
+	"github.com/s3nn1k/ef-mob-task/internal/config"
+)
+
+// NewAPIClient creates client of songs info API, requests are made over https if it's enabled in config
+func NewAPIClient(cfg config.API) (*client.Client, error) {
+	if !cfg.TLS {
+		return client.New(cfg.Host, cfg.Port), nil
+	}
+
+	tlsCfg, err := certs.ClientConfig(cfg.TLSCA, cfg.TLSCert, cfg.TLSKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.New(cfg.Host, cfg.Port, client.WithTLS(tlsCfg)), nil
+}
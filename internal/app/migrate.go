@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/s3nn1k/ef-mob-task/internal/config"
+	"github.com/s3nn1k/ef-mob-task/internal/migrator"
+	"github.com/s3nn1k/ef-mob-task/internal/storage/postgres"
+)
+
+// migrateTimeout limits waiting for other replicas and applying migrations
+const migrateTimeout = 5 * time.Minute
+
+// migrateSchema applies migrations if auto migration is enabled and checks that app can work with schema
+// Replicas that start at once wait for each other by advisory lock. Returns version of schema
+func migrateSchema(db *pgxpool.Pool, connStr string, cfg config.DB, log *slog.Logger) (uint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), migrateTimeout)
+	defer cancel()
+
+	unlock, err := postgres.LockMigrations(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	m, err := migrator.New(cfg.Migrations, connStr)
+	if err != nil {
+		return 0, err
+	}
+	defer m.Close()
+
+	status, err := m.Status()
+	if err != nil {
+		return 0, err
+	}
+
+	// Newer or dirty schema is checked before migration, migrate can't apply anything to it
+	if err := status.Check(false); err != nil {
+		return 0, err
+	}
+
+	if cfg.AutoMigrate && status.Version < status.Latest {
+		if err := m.Up(); err != nil {
+			return 0, err
+		}
+
+		log.Info("Applied migrations", slog.Uint64("from", uint64(status.Version)), slog.Uint64("to", uint64(status.Latest)))
+
+		if status, err = m.Status(); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := status.Check(true); err != nil {
+		return 0, err
+	}
+
+	log.Info("Checked schema", slog.Uint64("version", uint64(status.Version)), slog.Bool("autoMigrate", cfg.AutoMigrate))
+
+	return status.Version, nil
+}
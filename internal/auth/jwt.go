@@ -0,0 +1,206 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/s3nn1k/ef-mob-task/internal/config"
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+)
+
+// Supported signing algorithms
+const (
+	algHS256 = "HS256"
+	algRS256 = "RS256"
+)
+
+// type Claims represents claims of token that are used by the API
+type Claims struct {
+	Roles []string `json:"roles"`
+	jwt.RegisteredClaims
+}
+
+// type jwk represents json web key, only oct and RSA keys are supported
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	K   string `json:"k"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// type JWTVerifier verifies HS256 and RS256 tokens with keys from local JWKS file and secret from config
+type JWTVerifier struct {
+	// hmac and rsa keys are kept apart, so key of one type is never used for algorithm of another
+	hmac   map[string][]byte
+	rsa    map[string]*rsa.PublicKey
+	parser *jwt.Parser
+}
+
+// NewJWTVerifier loads keys from JWKS file and secret of config
+// Secret is used for HS256 tokens without kid
+func NewJWTVerifier(cfg config.JWT) (*JWTVerifier, error) {
+	v := &JWTVerifier{
+		hmac: make(map[string][]byte),
+		rsa:  make(map[string]*rsa.PublicKey),
+	}
+
+	if cfg.Secret != "" {
+		v.hmac[""] = []byte(cfg.Secret)
+	}
+
+	if cfg.JWKSFile != "" {
+		if err := v.loadJWKS(cfg.JWKSFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(v.hmac) == 0 && len(v.rsa) == 0 {
+		return nil, errors.New("jwt keys are not configured")
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{algHS256, algRS256}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(cfg.Leeway),
+	}
+
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+
+	v.parser = jwt.NewParser(opts...)
+
+	return v, nil
+}
+
+// Verify checks signature and claims of token and returns it's subject and roles
+func (v *JWTVerifier) Verify(token string) (models.Principal, error) {
+	var claims Claims
+
+	if _, err := v.parser.ParseWithClaims(token, &claims, v.key); err != nil {
+		return models.Principal{}, err
+	}
+
+	// Subject identifies client, e.g. for rate limiting, so tokens without it are not accepted
+	if claims.Subject == "" {
+		return models.Principal{}, errors.New("token has no subject")
+	}
+
+	return models.Principal{
+		Kind:    models.PrincipalToken,
+		Subject: claims.Subject,
+		Roles:   claims.Roles,
+	}, nil
+}
+
+// key returns key for token by it's algorithm and kid
+// Key without kid is used only if it's the only key for algorithm
+func (v *JWTVerifier) key(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	switch token.Method.Alg() {
+	case algHS256:
+		return lookup(v.hmac, kid)
+	case algRS256:
+		return lookup(v.rsa, kid)
+	default:
+		return nil, fmt.Errorf("unexpected signing method: %s", token.Method.Alg())
+	}
+}
+
+func lookup[T any](keys map[string]T, kid string) (T, error) {
+	if key, ok := keys[kid]; ok {
+		return key, nil
+	}
+
+	var zero T
+
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, nil
+		}
+	}
+
+	return zero, fmt.Errorf("unknown key: %q", kid)
+}
+
+// loadJWKS reads keys from JWKS file
+// Keys that are not intended for signatures are skipped
+func (v *JWTVerifier) loadJWKS(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("can't read jwks: %w", err)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+
+	if err := json.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("can't parse jwks: %w", err)
+	}
+
+	for i, key := range set.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+
+		switch key.Kty {
+		case "oct":
+			if key.Alg != "" && key.Alg != algHS256 {
+				continue
+			}
+
+			secret, err := base64.RawURLEncoding.DecodeString(key.K)
+			if err != nil || len(secret) == 0 {
+				return fmt.Errorf("jwks key %d: invalid k", i)
+			}
+
+			v.hmac[key.Kid] = secret
+		case "RSA":
+			if key.Alg != "" && key.Alg != algRS256 {
+				continue
+			}
+
+			pub, err := rsaKey(key)
+			if err != nil {
+				return fmt.Errorf("jwks key %d: %w", i, err)
+			}
+
+			v.rsa[key.Kid] = pub
+		}
+	}
+
+	return nil
+}
+
+// rsaKey converts modulus and exponent of jwk into public key
+func rsaKey(key jwk) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(key.N)
+	if err != nil || len(n) == 0 {
+		return nil, errors.New("invalid n")
+	}
+
+	e, err := base64.RawURLEncoding.DecodeString(key.E)
+	if err != nil || len(e) == 0 || len(e) > 4 {
+		return nil, errors.New("invalid e")
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/s3nn1k/ef-mob-task/internal/config"
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+)
+
+func TestVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	octKey := []byte("jwks-secret-of-at-least-32-bytes")
+
+	jwks, err := json.Marshal(map[string]any{
+		"keys": []map[string]string{
+			{
+				"kty": "RSA",
+				"kid": "rsa1",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+			},
+			{
+				"kty": "oct",
+				"kid": "oct1",
+				"k":   base64.RawURLEncoding.EncodeToString(octKey),
+			},
+			{
+				"kty": "RSA",
+				"kid": "enc1",
+				"use": "enc",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	secret := []byte("config-secret-of-at-least-32-bytes")
+
+	verifier, err := NewJWTVerifier(config.JWT{JWKSFile: path, Secret: string(secret), Issuer: "auth"})
+	if err != nil {
+		t.Fatalf("error not expected while loading keys: %s", err)
+	}
+
+	claims := func(exp time.Duration, iss string) Claims {
+		return Claims{
+			Roles: []string{models.ScopeWrite},
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   "user",
+				Issuer:    iss,
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(exp)),
+			},
+		}
+	}
+
+	sign := func(method jwt.SigningMethod, kid string, key any, claims Claims) string {
+		token := jwt.NewWithClaims(method, claims)
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return signed
+	}
+
+	// Public key signed as HS256 secret must not be accepted
+	pubBytes := rsaKey.N.Bytes()
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{
+			name:  "rs256",
+			token: sign(jwt.SigningMethodRS256, "rsa1", rsaKey, claims(time.Minute, "auth")),
+		},
+		{
+			name:  "hs256 jwks",
+			token: sign(jwt.SigningMethodHS256, "oct1", octKey, claims(time.Minute, "auth")),
+		},
+		{
+			name:  "hs256 secret",
+			token: sign(jwt.SigningMethodHS256, "", secret, claims(time.Minute, "auth")),
+		},
+		{
+			name:    "expired",
+			token:   sign(jwt.SigningMethodRS256, "rsa1", rsaKey, claims(-time.Minute, "auth")),
+			wantErr: true,
+		},
+		{
+			name:    "wrong issuer",
+			token:   sign(jwt.SigningMethodRS256, "rsa1", rsaKey, claims(time.Minute, "other")),
+			wantErr: true,
+		},
+		{
+			name:    "unknown kid",
+			token:   sign(jwt.SigningMethodRS256, "rsa2", rsaKey, claims(time.Minute, "auth")),
+			wantErr: true,
+		},
+		{
+			name:    "algorithm confusion",
+			token:   sign(jwt.SigningMethodHS256, "rsa1", pubBytes, claims(time.Minute, "auth")),
+			wantErr: true,
+		},
+		{
+			name: "no subject",
+			token: sign(jwt.SigningMethodRS256, "rsa1", rsaKey, func() Claims {
+				c := claims(time.Minute, "auth")
+				c.Subject = ""
+
+				return c
+			}()),
+			wantErr: true,
+		},
+		{
+			name:    "hs512",
+			token:   sign(jwt.SigningMethodHS512, "", secret, claims(time.Minute, "auth")),
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			principal, err := verifier.Verify(test.token)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("error: want error %v, but got %v", test.wantErr, err)
+			}
+
+			if err == nil && (principal.Subject != "user" || !slices.Equal(principal.Roles, []string{models.ScopeWrite})) {
+				t.Fatalf("error: want subject and roles of token, but got %+v", principal)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// reloadInterval is the period of checking that certificate files were changed
+const reloadInterval = 10 * time.Second
+
+// Modes of client certificates verification
+const (
+	ClientAuthOptional = "optional"
+	ClientAuthRequire  = "require"
+)
+
+// type Reloader keeps certificate and reloads it when files are changed
+// Used by tls.Config, so renewed certificates are applied without restart
+type Reloader struct {
+	log      *slog.Logger
+	certFile string
+	keyFile  string
+
+	mu      sync.RWMutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+// NewReloader loads certificate and key in PEM format
+func NewReloader(log *slog.Logger, certFile string, keyFile string) (*Reloader, error) {
+	r := &Reloader{
+		log:      log,
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// GetCertificate returns current certificate, used as tls.Config.GetCertificate
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.cert, nil
+}
+
+// Watch reloads certificate when files are changed until context is done
+// Previous certificate is kept if new one can't be loaded
+func (r *Reloader) Watch(ctx context.Context) error {
+	ticker := time.NewTicker(reloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			modTime, err := r.lastModified()
+			if err != nil {
+				r.log.Error("Can't check certificate files: " + err.Error())
+				continue
+			}
+
+			r.mu.RLock()
+			changed := modTime.After(r.modTime)
+			r.mu.RUnlock()
+
+			if !changed {
+				continue
+			}
+
+			if err := r.reload(); err != nil {
+				r.log.Error("Can't reload certificate: " + err.Error())
+				continue
+			}
+
+			r.log.Info("Reloaded certificate", slog.String("cert", r.certFile))
+		}
+	}
+}
+
+// reload loads certificate and remembers time of the last change of files
+func (r *Reloader) reload() error {
+	modTime, err := r.lastModified()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("can't load certificate: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cert = &cert
+	r.modTime = modTime
+
+	return nil
+}
+
+// lastModified returns time of the last change of certificate or key
+func (r *Reloader) lastModified() (time.Time, error) {
+	var last time.Time
+
+	for _, file := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if info.ModTime().After(last) {
+			last = info.ModTime()
+		}
+	}
+
+	return last, nil
+}
+
+// ServerConfig returns config of server with HTTP/2 support
+// Client certificates are verified by CA if it's set, mode is optional or require
+func ServerConfig(r *Reloader, clientCA string, clientAuth string) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: r.GetCertificate,
+		NextProtos:     []string{"h2", "http/1.1"},
+	}
+
+	if clientCA == "" {
+		return cfg, nil
+	}
+
+	pool, err := loadPool(clientCA)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.ClientCAs = pool
+
+	switch clientAuth {
+	case "", ClientAuthOptional:
+		cfg.ClientAuth = tls.VerifyClientCertIfGiven
+	case ClientAuthRequire:
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("unknown client auth %q, must be optional or require", clientAuth)
+	}
+
+	return cfg, nil
+}
+
+// ClientConfig returns config of client, that trusts system roots and CA if it's set
+// Client certificate is sent if cert and key are set
+func ClientConfig(caFile string, certFile string, keyFile string) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if caFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if err := appendCerts(pool, caFile); err != nil {
+			return nil, err
+		}
+
+		cfg.RootCAs = pool
+	}
+
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("can't load client certificate: %w", err)
+		}
+
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// loadPool creates pool of certificates from PEM file
+func loadPool(file string) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+
+	if err := appendCerts(pool, file); err != nil {
+		return nil, err
+	}
+
+	return pool, nil
+}
+
+// appendCerts adds certificates from PEM file to pool
+func appendCerts(pool *x509.CertPool, file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	if !pool.AppendCertsFromPEM(data) {
+		return errors.New("no certificates found in " + file)
+	}
+
+	return nil
+}
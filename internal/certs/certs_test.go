@@ -0,0 +1,198 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// issue creates certificate signed by parent or self-signed one if parent is nil
+func issue(t *testing.T, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, []byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage |= x509.KeyUsageCertSign
+		parent, parentKey = tmpl, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return cert, key,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+// write writes data into file of the given dir and returns it's path
+func write(t *testing.T, dir string, name string, data []byte) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	return path
+}
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	_, _, certPem, keyPem := issue(t, "first", nil, nil)
+	certFile := write(t, dir, "cert.pem", certPem)
+	keyFile := write(t, dir, "key.pem", keyPem)
+
+	r, err := NewReloader(log, certFile, keyFile)
+	require.NoError(t, err)
+
+	cert, err := r.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "first", cert.Leaf.Subject.CommonName)
+
+	_, _, certPem, keyPem = issue(t, "second", nil, nil)
+	write(t, dir, "cert.pem", certPem)
+	write(t, dir, "key.pem", keyPem)
+
+	require.NoError(t, r.reload())
+
+	cert, err = r.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "second", cert.Leaf.Subject.CommonName)
+
+	// Previous certificate is kept if files are broken
+	write(t, dir, "key.pem", []byte("broken"))
+
+	assert.Error(t, r.reload())
+
+	cert, err = r.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "second", cert.Leaf.Subject.CommonName)
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	ca, caKey, caPem, _ := issue(t, "ca", nil, nil)
+	caFile := write(t, dir, "ca.pem", caPem)
+
+	_, _, srvCert, srvKey := issue(t, "server", ca, caKey)
+	_, _, clntCert, clntKey := issue(t, "client", ca, caKey)
+
+	r, err := NewReloader(log, write(t, dir, "server.pem", srvCert), write(t, dir, "server-key.pem", srvKey))
+	require.NoError(t, err)
+
+	srvCfg, err := ServerConfig(r, caFile, ClientAuthRequire)
+	require.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto + " " + r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	srv.EnableHTTP2 = true
+	srv.TLS = srvCfg
+	srv.StartTLS()
+	defer srv.Close()
+
+	get := func(cfg *tls.Config) (string, error) {
+		clnt := &http.Client{Transport: &http.Transport{TLSClientConfig: cfg, ForceAttemptHTTP2: true}}
+
+		// Name is sent in SNI, otherwise test certificate of httptest is used instead of reloader
+		resp, err := clnt.Get(strings.Replace(srv.URL, "127.0.0.1", "localhost", 1))
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+
+		return string(body), err
+	}
+
+	clntCfg, err := ClientConfig(caFile, write(t, dir, "client.pem", clntCert), write(t, dir, "client-key.pem", clntKey))
+	require.NoError(t, err)
+
+	body, err := get(clntCfg)
+	require.NoError(t, err)
+	assert.Equal(t, "HTTP/2.0 client", body)
+
+	// Client without certificate is rejected
+	clntCfg, err = ClientConfig(caFile, "", "")
+	require.NoError(t, err)
+
+	_, err = get(clntCfg)
+	assert.Error(t, err)
+}
+
+func TestServerConfigClientAuth(t *testing.T) {
+	dir := t.TempDir()
+
+	_, _, caPem, _ := issue(t, "ca", nil, nil)
+	caFile := write(t, dir, "ca.pem", caPem)
+
+	tests := []struct {
+		name    string
+		mode    string
+		want    tls.ClientAuthType
+		wantErr bool
+	}{
+		{name: "Default", mode: "", want: tls.VerifyClientCertIfGiven},
+		{name: "Optional", mode: ClientAuthOptional, want: tls.VerifyClientCertIfGiven},
+		{name: "Require", mode: ClientAuthRequire, want: tls.RequireAndVerifyClientCert},
+		{name: "Unknown", mode: "always", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ServerConfig(&Reloader{}, caFile, tt.mode)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.ClientAuth)
+		})
+	}
+}
@@ -2,7 +2,9 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
@@ -10,6 +12,11 @@ import (
 
 	"github.com/s3nn1k/ef-mob-task/internal/models"
 	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type ClientIface interface {
@@ -21,8 +28,23 @@ type Client struct {
 	basePath url.URL
 }
 
-func New(host string, port string) *Client {
-	return &Client{
+// type Option configures Client
+type Option func(c *Client)
+
+// WithTLS makes requests to API over https with the given config
+// Transport is cloned from default one to keep it's proxy, dial and idle connection timeouts
+func WithTLS(cfg *tls.Config) Option {
+	return func(c *Client) {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = cfg
+
+		c.basePath.Scheme = "https"
+		c.client.Transport = transport
+	}
+}
+
+func New(host string, port string, opts ...Option) *Client {
+	c := &Client{
 		client: &http.Client{},
 		basePath: url.URL{
 			Scheme: "http",
@@ -30,9 +52,31 @@ func New(host string, port string) *Client {
 			Path:   "/info",
 		},
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
-func (c *Client) GetDetail(ctx context.Context, song string, group string) (models.Song, error) {
+// headerRequestId is the header that forwards id of incoming request to API
+const headerRequestId = "X-Request-ID"
+
+// tracerName is the name of tracer of API requests
+const tracerName = "github.com/s3nn1k/ef-mob-task/internal/client"
+
+func (c *Client) GetDetail(ctx context.Context, song string, group string) (res models.Song, err error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "Client.GetDetail", trace.WithSpanKind(trace.SpanKindClient))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+
+		span.End()
+	}()
+
 	logger.LogUse(ctx).Debug("Client.GetDetail", "input", slog.String("song", song), slog.String("group", group))
 
 	p := url.Values{}
@@ -45,6 +89,17 @@ func (c *Client) GetDetail(ctx context.Context, song string, group string) (mode
 	}
 
 	req.URL.RawQuery = p.Encode()
+
+	// Upstream continues the trace with traceparent header
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	// Logs of upstream are correlated by the same request id
+	if id := logger.RequestId(ctx); id != "" {
+		req.Header.Set(headerRequestId, id)
+	}
+
+	span.SetAttributes(attribute.String("url.full", req.URL.String()))
+
 	logger.LogUse(ctx).Info("Do Request", slog.String("url", req.URL.String()))
 
 	resp, err := c.client.Do(req)
@@ -52,20 +107,41 @@ func (c *Client) GetDetail(ctx context.Context, song string, group string) (mode
 		return models.Song{}, err
 	}
 
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return models.Song{}, err
 	}
 
-	var res models.Song
-
 	if err := json.Unmarshal(body, &res); err != nil {
 		return models.Song{}, err
 	}
 
 	resp.Body.Close()
 
-	logger.LogUse(ctx).Debug("Result", slog.Any("song", res.AsLogValue()))
+	logger.LogUse(ctx).Debug("Result", slog.Any("song", res.LogValue()))
 
 	return res, nil
 }
+
+// Ping checks that API is reachable, any response except server errors means that API is up
+func (c *Client) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.basePath.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("api responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestGetDetailPropagation(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var traceparent, requestId string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		requestId = r.Header.Get("X-Request-ID")
+
+		w.Write([]byte(`{"text":"TestText","link":"TestLink","releaseDate":"16.07.2006"}`))
+	}))
+	defer server.Close()
+
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	song, err := New(host, port).GetDetail(logger.NewCtxWithRequestId(context.Background(), "TestRequestId"), "TestSong", "TestGroup")
+	if err != nil {
+		t.Fatalf("error not expected while getting detail: %s", err)
+	}
+
+	if song.Text != "TestText" {
+		t.Fatalf("error: want song details, but got %+v", song)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "Client.GetDetail" {
+		t.Fatalf("error: want client span, but got %+v", spans)
+	}
+
+	want := "00-" + spans[0].SpanContext.TraceID().String() + "-" + spans[0].SpanContext.SpanID().String() + "-01"
+	if traceparent != want {
+		t.Fatalf("error: want traceparent %s, but got %s", want, traceparent)
+	}
+
+	if requestId != "TestRequestId" {
+		t.Fatalf("error: request id must be forwarded, but got %q", requestId)
+	}
+}
+
+func TestWithTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"text":"TestText","link":"TestLink","releaseDate":"16.07.2006"}`))
+	}))
+	defer server.Close()
+
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+
+	song, err := New(host, port, WithTLS(&tls.Config{RootCAs: pool})).GetDetail(context.Background(), "TestSong", "TestGroup")
+	if err != nil {
+		t.Fatalf("error not expected while getting detail over https: %s", err)
+	}
+
+	if song.Text != "TestText" {
+		t.Fatalf("error: want song details, but got %+v", song)
+	}
+
+	transport := New(host, port, WithTLS(&tls.Config{RootCAs: pool})).client.Transport.(*http.Transport)
+
+	if transport.Proxy == nil || transport.TLSHandshakeTimeout == 0 || transport.IdleConnTimeout == 0 {
+		t.Fatal("error: transport must keep settings of default transport")
+	}
+
+	if transport == http.DefaultTransport {
+		t.Fatal("error: default transport must not be changed")
+	}
+
+	// Plain http client can't talk to https server
+	if _, err := New(host, port).GetDetail(context.Background(), "TestSong", "TestGroup"); err == nil {
+		t.Fatal("error: want error of plain http request to https server")
+	}
+}
@@ -19,8 +19,9 @@ func (d *Dummy) Run() error {
 	return d.server.ListenAndServe()
 }
 
-func (d *Dummy) Stop() error {
-	return d.server.Shutdown(context.Background())
+// Stop stops accepting requests and waits for in-flight ones until context is done
+func (d *Dummy) Stop(ctx context.Context) error {
+	return d.server.Shutdown(ctx)
 }
 
 func New(cfg config.API) *Dummy {
@@ -1,18 +1,73 @@
 package config
 
 import (
+	"fmt"
 	"log/slog"
-	"os"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+)
+
+// defaultConcurrency is the count of concurrent requests to API if API_CONCURRENCY is not set
+const defaultConcurrency = 8
+
+// defaultServiceName is the name of service in traces if TRACE_SERVICE_NAME is not set
+const defaultServiceName = "songs"
+
+// Defaults of server and tokens timeouts
+const (
+	defaultTimeout     = 10 * time.Second
+	defaultIdleTimeout = time.Minute
+	defaultLeeway      = 30 * time.Second
+
+	defaultShutdownTimeout = 15 * time.Second
+	defaultDrainDelay      = 5 * time.Second
 )
 
+// type Config represents configuration of app
+// Config is loaded by Load from defaults, file, environment and flags
 type Config struct {
 	Level      string
 	UseTestApi bool
 
-	DB     DB
-	API    API
-	Server Server
+	Log       Log
+	DB        DB
+	API       API
+	Server    Server
+	JWT       JWT
+	RateLimit RateLimit
+	Tracing   Tracing
+}
+
+// Defaults of log file rotation
+const (
+	defaultLogMaxSize    = 100
+	defaultLogMaxBackups = 3
+	defaultLogMaxAge     = 28
+)
+
+// Defaults of logging policy
+const (
+	defaultLogMaxFieldLen = 256
+	defaultLogMaxItems    = 10
+)
+
+// type Log represents format, output and policy of logs
+// Logs are written to stdout if file is empty, debug logs are not sampled if SampleFirst is zero
+type Log struct {
+	Format     string
+	File       string
+	MaxSize    int
+	MaxBackups int
+	MaxAge     int
+
+	MaxFieldLen      int
+	MaxItems         int
+	RedactText       bool
+	SampleFirst      int
+	SampleThereafter int
 }
 
 // type DB represents neccessary data to connect postgres
@@ -22,94 +77,248 @@ type DB struct {
 	User string
 	Pass string
 	Name string
+	// Migrations is the url of migrations source, e.g. file:///migrations, embedded migrations are used if it's empty
+	Migrations string
+	// AutoMigrate applies migrations on start, otherwise app refuses to start with outdated schema
+	AutoMigrate bool
 }
 
 // type API represents neccessary data for making requests
 type API struct {
-	Host string
-	Port string
+	Host        string
+	Port        string
+	Concurrency int
+	// HealthCheck makes readiness depend on API reachability
+	HealthCheck bool
+	// TLS makes requests over https, CA is trusted in addition to system roots, cert and key are sent to API
+	TLS     bool
+	TLSCA   string
+	TLSCert string
+	TLSKey  string
 }
 
 // type Server represents neccessary data to init server
 type Server struct {
-	Host        string
-	Port        string
+	Host string
+	Port string
+	// GRPCEnabled starts gRPC server on GRPCPort
+	GRPCEnabled bool
+	GRPCPort    string
 	Timeout     time.Duration
 	IdleTimeout time.Duration
+	Auth        bool
+	// ShutdownTimeout limits every phase of shutdown, e.g. draining of in-flight requests
+	ShutdownTimeout time.Duration
+	// DrainDelay is the time between failing readiness and closing listeners, so balancer notices it
+	// Delay is a part of draining phase, so it must be less than shutdown timeout
+	DrainDelay time.Duration
+	// Server listens https if cert and key are set, certificates of clients are verified by CA if it's set
+	TLSCert       string
+	TLSKey        string
+	TLSClientCA   string
+	TLSClientAuth string
+}
+
+// TLSEnabled checks that server listens https
+func (s *Server) TLSEnabled() bool {
+	return s.TLSCert != "" && s.TLSKey != ""
+}
+
+// type Limit represents count of requests that are allowed per period
+// Zero limit means no limit
+type Limit struct {
+	Count  int
+	Period time.Duration
+}
+
+// type RateLimit represents limits of requests per client
+// Routes are keyed by patterns like "POST /songs", default limit is used for other routes.
+// Addr limits requests of remote address to all routes before auth, it's shared by all clients behind one proxy or NAT
+type RateLimit struct {
+	Default Limit
+	Routes  map[string]Limit
+	Addr    Limit
+}
+
+// ParseLimit parses limit in format count/unit, e.g. 100/m
+// Available units are s, m and h
+func ParseLimit(val string) (Limit, error) {
+	count, unit, ok := strings.Cut(strings.TrimSpace(val), "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid limit %q, must be in format count/unit", val)
+	}
+
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 0 {
+		return Limit{}, fmt.Errorf("invalid limit %q, count must be positive int", val)
+	}
+
+	periods := map[string]time.Duration{"s": time.Second, "m": time.Minute, "h": time.Hour}
+
+	period, ok := periods[unit]
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid limit %q, unit must be s, m or h", val)
+	}
+
+	return Limit{Count: n, Period: period}, nil
+}
+
+// String returns limit in format count/unit
+func (l Limit) String() string {
+	if l.Count == 0 {
+		return "unlimited"
+	}
+
+	return fmt.Sprintf("%d/%s", l.Count, l.Period)
+}
+
+// LogValue represents RateLimit struct as slog.Value
+// Used for logging
+func (r *RateLimit) LogValue() slog.Value {
+	attrs := []slog.Attr{slog.String("default", r.Default.String()), slog.String("addr", r.Addr.String())}
+
+	for route, limit := range r.Routes {
+		attrs = append(attrs, slog.String(route, limit.String()))
+	}
+
+	return slog.GroupValue(attrs...)
 }
 
-// AsLogValue represents DB struct as slog.Value
+// Available exporters of traces
+const (
+	ExporterNone   = ""
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// type Tracing represents neccessary data to export traces
+// Traces are not exported if exporter is empty, but trace context is still propagated
+type Tracing struct {
+	Exporter    string
+	Endpoint    string
+	ServiceName string
+	SampleRatio float64
+}
+
+// LogValue represents Tracing struct as slog.Value
 // Used for logging
-func (db *DB) AsLogValue() slog.Value {
+func (t *Tracing) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("exporter", t.Exporter),
+		slog.String("endpoint", t.Endpoint),
+		slog.String("serviceName", t.ServiceName),
+		slog.Float64("sampleRatio", t.SampleRatio),
+	)
+}
+
+// type JWT represents neccessary data to verify bearer tokens
+// Tokens are accepted only if JWKS file or secret is set
+type JWT struct {
+	JWKSFile string
+	Secret   string
+	Issuer   string
+	Audience string
+	Leeway   time.Duration
+}
+
+// Enabled checks that keys for tokens are configured
+func (j *JWT) Enabled() bool {
+	return j.JWKSFile != "" || j.Secret != ""
+}
+
+// LogValue represents JWT struct as slog.Value
+// Used for logging
+func (j *JWT) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("jwks", j.JWKSFile),
+		logger.Secret("secret", j.Secret),
+		slog.String("issuer", j.Issuer),
+		slog.String("audience", j.Audience),
+		slog.Duration("leeway", j.Leeway),
+	)
+}
+
+// LogValue represents Log struct as slog.Value
+// Used for logging
+func (l *Log) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("format", l.Format),
+		slog.String("file", l.File),
+		slog.Int("maxSizeMb", l.MaxSize),
+		slog.Int("maxBackups", l.MaxBackups),
+		slog.Int("maxAgeDays", l.MaxAge),
+		slog.Int("maxFieldLen", l.MaxFieldLen),
+		slog.Int("maxItems", l.MaxItems),
+		slog.Bool("redactText", l.RedactText),
+		slog.Int("sampleFirst", l.SampleFirst),
+		slog.Int("sampleThereafter", l.SampleThereafter),
+	)
+}
+
+// LogValue represents DB struct as slog.Value
+// Used for logging
+func (db *DB) LogValue() slog.Value {
 	return slog.GroupValue(
 		slog.String("host", db.Host),
 		slog.String("port", db.Port),
 		slog.String("user", db.User),
-		slog.String("pass", "[hidden]"),
+		logger.Secret("pass", db.Pass),
 		slog.String("name", db.Name),
+		slog.String("migrations", db.Migrations),
+		slog.Bool("autoMigrate", db.AutoMigrate),
 	)
 }
 
-// AsLogValue represents API struct as slog.Value
+// ConnString returns connection string for postgres
+func (db *DB) ConnString() string {
+	return fmt.Sprintf("postgresql://%s:%s@%s:%s/%s?sslmode=disable", db.User, db.Pass, db.Host, db.Port, db.Name)
+}
+
+// LogValue represents API struct as slog.Value
 // Used for logging
-func (a *API) AsLogValue() slog.Value {
+func (a *API) LogValue() slog.Value {
 	return slog.GroupValue(
 		slog.String("host", a.Host),
 		slog.String("port", a.Port),
+		slog.Int("concurrency", a.Concurrency),
+		slog.Bool("healthCheck", a.HealthCheck),
+		slog.Bool("tls", a.TLS),
+		slog.String("tlsCa", a.TLSCA),
+		slog.String("tlsCert", a.TLSCert),
 	)
 }
 
-// AsLogValue represents Server struct as slog.Value
+// LogValue represents Server struct as slog.Value
 // Used for logging
-func (s *Server) AsLogValue() slog.Value {
+func (s *Server) LogValue() slog.Value {
 	return slog.GroupValue(
 		slog.String("host", s.Host),
 		slog.String("port", s.Port),
+		slog.Bool("grpcEnabled", s.GRPCEnabled),
+		slog.String("grpcPort", s.GRPCPort),
 		slog.Duration("timeout", s.Timeout),
 		slog.Duration("idleTimeout", s.IdleTimeout),
+		slog.Bool("auth", s.Auth),
+		slog.Duration("shutdownTimeout", s.ShutdownTimeout),
+		slog.Duration("drainDelay", s.DrainDelay),
+		slog.String("tlsCert", s.TLSCert),
+		slog.String("tlsClientCa", s.TLSClientCA),
+		slog.String("tlsClientAuth", s.TLSClientAuth),
 	)
 }
 
-// LoadFromEnv loads config var's from environment
-func LoadFromEnv() (*Config, error) {
-	cfg := &Config{
-		Level: os.Getenv("LOG_LEVEL"),
-		DB: DB{
-			Host: os.Getenv("DB_HOST"),
-			Port: os.Getenv("DB_PORT"),
-			User: os.Getenv("DB_USER"),
-			Pass: os.Getenv("DB_PASS"),
-			Name: os.Getenv("DB_NAME"),
-		},
-		API: API{
-			Host: os.Getenv("API_HOST"),
-			Port: os.Getenv("API_PORT"),
-		},
-		Server: Server{
-			Host: os.Getenv("SERVER_HOST"),
-			Port: os.Getenv("SERVER_PORT"),
-		},
-	}
-
-	use := os.Getenv("USE_TEST_API")
-	if use == "true" {
-		cfg.UseTestApi = true
-	} else {
-		cfg.UseTestApi = false
-	}
-
-	timeout, err := time.ParseDuration(os.Getenv("SERVER_TIMEOUT"))
-	if err != nil {
-		return nil, err
-	}
-
-	idleTimeout, err := time.ParseDuration(os.Getenv("IDLE_TIMEOUT"))
-	if err != nil {
-		return nil, err
-	}
-
-	cfg.Server.Timeout = timeout
-	cfg.Server.IdleTimeout = idleTimeout
-
-	return cfg, nil
+// LogValue represents Config struct as slog.Value with hidden secrets
+// Used to print effective config
+func (c *Config) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("level", c.Level),
+		slog.Bool("useTestApi", c.UseTestApi),
+		slog.Any("log", c.Log.LogValue()),
+		slog.Any("db", c.DB.LogValue()),
+		slog.Any("api", c.API.LogValue()),
+		slog.Any("server", c.Server.LogValue()),
+		slog.Any("jwt", c.JWT.LogValue()),
+		slog.Any("rateLimit", c.RateLimit.LogValue()),
+		slog.Any("tracing", c.Tracing.LogValue()),
+	)
 }
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	limit, err := ParseLimit("10/m")
+	if err != nil || limit.Count != 10 || limit.Period != time.Minute {
+		t.Fatalf("error: want 10 per minute, but got %+v, %v", limit, err)
+	}
+
+	for _, val := range []string{"10", "ten/s", "10/d", "-1/s"} {
+		if _, err := ParseLimit(val); err == nil {
+			t.Fatalf("error: want error for %q", val)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	file := filepath.Join(dir, "config.yaml")
+
+	data := `
+log:
+  level: warning
+db:
+  host: filehost
+  port: 5433
+server:
+  port: 9000
+  idle_timeout: 2m
+rate_limit:
+  addr: 600/m
+  routes:
+    POST /songs: 10/m
+`
+	if err := os.WriteFile(file, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("DB_HOST", "envhost")
+	t.Setenv("DB_NAME", "")
+	t.Setenv("SERVER_GRPC_ENABLED", "false")
+	t.Setenv("SERVER_GRPC_PORT", "9100")
+
+	cfg, args, err := Load([]string{"-config", file, "-server.port", "9100", "songs", "list"})
+	if err != nil {
+		t.Fatalf("error not expected while loading: %s", err)
+	}
+
+	if cfg.Level != "warning" || cfg.DB.Port != "5433" || cfg.Server.IdleTimeout != 2*time.Minute {
+		t.Fatalf("error: values of file must override defaults, but got %+v", cfg)
+	}
+
+	if cfg.DB.Host != "envhost" || cfg.DB.Name != "postgres" {
+		t.Fatalf("error: non-empty env must override file, but got %+v", cfg.DB)
+	}
+
+	if cfg.Server.Port != "9100" {
+		t.Fatalf("error: flag must override file, but got %s", cfg.Server.Port)
+	}
+
+	// Port of disabled gRPC server is not validated
+	if cfg.Server.GRPCEnabled {
+		t.Fatal("error: grpc server must be disabled by env")
+	}
+
+	if cfg.RateLimit.Routes["POST /songs"] != (Limit{Count: 10, Period: time.Minute}) {
+		t.Fatalf("error: want route limit from file, but got %+v", cfg.RateLimit.Routes)
+	}
+
+	if cfg.RateLimit.Addr != (Limit{Count: 600, Period: time.Minute}) {
+		t.Fatalf("error: want address limit from file, but got %+v", cfg.RateLimit.Addr)
+	}
+
+	if len(args) != 2 || args[0] != "songs" {
+		t.Fatalf("error: want command args after flags, but got %v", args)
+	}
+}
+
+func TestLoadToml(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.toml")
+
+	data := `
+use_test_api = true
+
+[api]
+concurrency = 4
+
+[tracing]
+sample_ratio = 0.5
+`
+	if err := os.WriteFile(file, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("CONFIG_FILE", file)
+
+	cfg, _, err := Load(nil)
+	if err != nil {
+		t.Fatalf("error not expected while loading: %s", err)
+	}
+
+	if !cfg.UseTestApi || cfg.API.Concurrency != 4 || cfg.Tracing.SampleRatio != 0.5 {
+		t.Fatalf("error: want values from toml file, but got %+v", cfg)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+
+	if err := os.WriteFile(file, []byte("db:\n  hots: db\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("SERVER_TIMEOUT", "4")
+
+	_, _, err := Load([]string{"-config", file, "-log.level", "verbose", "-server.port", "70000", "-server.tls-cert", "cert.pem", "-server.tls-client-auth", "always", "-server.drain-delay", "1m"})
+	if err == nil {
+		t.Fatal("error: want error for invalid config")
+	}
+
+	for _, want := range []string{`"db.hots"`, "env SERVER_TIMEOUT", "verbose", "server port", "tls cert and key", "always", "drain delay"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error: want all errors at once, %q is missing in %s", want, err)
+		}
+	}
+}
@@ -0,0 +1,379 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// envConfigFile is the environment variable with path to config file, used if -config flag is not set
+const envConfigFile = "CONFIG_FILE"
+
+// type setting represents one config value, that could be set by file key, environment variable or flag
+// Flag name is the file key with dashes instead of underscores, e.g. -server.idle-timeout
+type setting struct {
+	key string
+	env string
+	set func(val string) error
+}
+
+// flagName returns name of setting's flag
+func (s setting) flagName() string {
+	return strings.ReplaceAll(s.key, "_", "-")
+}
+
+// Default returns config with default values, that are used if value is not set by file, environment or flag
+func Default() *Config {
+	return &Config{
+		Level: "info",
+		Log: Log{
+			Format:      "text",
+			MaxSize:     defaultLogMaxSize,
+			MaxBackups:  defaultLogMaxBackups,
+			MaxAge:      defaultLogMaxAge,
+			MaxFieldLen: defaultLogMaxFieldLen,
+			MaxItems:    defaultLogMaxItems,
+		},
+		DB: DB{
+			Host: "localhost",
+			Port: "5432",
+			User: "postgres",
+			Name: "postgres",
+
+			AutoMigrate: true,
+		},
+		API: API{
+			Host:        "localhost",
+			Port:        "8081",
+			Concurrency: defaultConcurrency,
+		},
+		Server: Server{
+			Port:        "8080",
+			GRPCEnabled: true,
+			GRPCPort:    "9090",
+			Timeout:     defaultTimeout,
+			IdleTimeout: defaultIdleTimeout,
+			Auth:        true,
+
+			ShutdownTimeout: defaultShutdownTimeout,
+			DrainDelay:      defaultDrainDelay,
+		},
+		JWT: JWT{
+			Leeway: defaultLeeway,
+		},
+		Tracing: Tracing{
+			ServiceName: defaultServiceName,
+			SampleRatio: 1,
+		},
+	}
+}
+
+// settings returns all values of config, that could be overridden
+func (c *Config) settings() []setting {
+	return []setting{
+		{"log.level", "LOG_LEVEL", setString(&c.Level)},
+		{"log.format", "LOG_FORMAT", setString(&c.Log.Format)},
+		{"log.file", "LOG_FILE", setString(&c.Log.File)},
+		{"log.max_size", "LOG_MAX_SIZE", setInt(&c.Log.MaxSize)},
+		{"log.max_backups", "LOG_MAX_BACKUPS", setInt(&c.Log.MaxBackups)},
+		{"log.max_age", "LOG_MAX_AGE", setInt(&c.Log.MaxAge)},
+		{"log.max_field_len", "LOG_MAX_FIELD_LEN", setInt(&c.Log.MaxFieldLen)},
+		{"log.max_items", "LOG_MAX_ITEMS", setInt(&c.Log.MaxItems)},
+		{"log.redact_text", "LOG_REDACT_TEXT", setBool(&c.Log.RedactText)},
+		{"log.sample_first", "LOG_SAMPLE_FIRST", setInt(&c.Log.SampleFirst)},
+		{"log.sample_thereafter", "LOG_SAMPLE_THEREAFTER", setInt(&c.Log.SampleThereafter)},
+
+		{"use_test_api", "USE_TEST_API", setBool(&c.UseTestApi)},
+
+		{"db.host", "DB_HOST", setString(&c.DB.Host)},
+		{"db.port", "DB_PORT", setString(&c.DB.Port)},
+		{"db.user", "DB_USER", setString(&c.DB.User)},
+		{"db.pass", "DB_PASS", setString(&c.DB.Pass)},
+		{"db.name", "DB_NAME", setString(&c.DB.Name)},
+		{"db.migrations", "DB_MIGRATIONS", setString(&c.DB.Migrations)},
+		{"db.auto_migrate", "DB_AUTO_MIGRATE", setBool(&c.DB.AutoMigrate)},
+
+		{"api.host", "API_HOST", setString(&c.API.Host)},
+		{"api.port", "API_PORT", setString(&c.API.Port)},
+		{"api.concurrency", "API_CONCURRENCY", setInt(&c.API.Concurrency)},
+		{"api.health_check", "API_HEALTH_CHECK", setBool(&c.API.HealthCheck)},
+		{"api.tls", "API_TLS", setBool(&c.API.TLS)},
+		{"api.tls_ca", "API_TLS_CA", setString(&c.API.TLSCA)},
+		{"api.tls_cert", "API_TLS_CERT", setString(&c.API.TLSCert)},
+		{"api.tls_key", "API_TLS_KEY", setString(&c.API.TLSKey)},
+
+		{"server.host", "SERVER_HOST", setString(&c.Server.Host)},
+		{"server.port", "SERVER_PORT", setString(&c.Server.Port)},
+		{"server.grpc_enabled", "SERVER_GRPC_ENABLED", setBool(&c.Server.GRPCEnabled)},
+		{"server.grpc_port", "SERVER_GRPC_PORT", setString(&c.Server.GRPCPort)},
+		{"server.timeout", "SERVER_TIMEOUT", setDuration(&c.Server.Timeout)},
+		{"server.idle_timeout", "IDLE_TIMEOUT", setDuration(&c.Server.IdleTimeout)},
+		{"server.auth", "SERVER_AUTH", setBool(&c.Server.Auth)},
+		{"server.shutdown_timeout", "SERVER_SHUTDOWN_TIMEOUT", setDuration(&c.Server.ShutdownTimeout)},
+		{"server.drain_delay", "SERVER_DRAIN_DELAY", setDuration(&c.Server.DrainDelay)},
+		{"server.tls_cert", "SERVER_TLS_CERT", setString(&c.Server.TLSCert)},
+		{"server.tls_key", "SERVER_TLS_KEY", setString(&c.Server.TLSKey)},
+		{"server.tls_client_ca", "SERVER_TLS_CLIENT_CA", setString(&c.Server.TLSClientCA)},
+		{"server.tls_client_auth", "SERVER_TLS_CLIENT_AUTH", setString(&c.Server.TLSClientAuth)},
+
+		{"jwt.jwks_file", "JWT_JWKS_FILE", setString(&c.JWT.JWKSFile)},
+		{"jwt.secret", "JWT_SECRET", setString(&c.JWT.Secret)},
+		{"jwt.issuer", "JWT_ISSUER", setString(&c.JWT.Issuer)},
+		{"jwt.audience", "JWT_AUDIENCE", setString(&c.JWT.Audience)},
+		{"jwt.leeway", "JWT_LEEWAY", setDuration(&c.JWT.Leeway)},
+
+		{"rate_limit.default", "RATE_LIMIT", setLimit(&c.RateLimit.Default)},
+		{"rate_limit.routes", "RATE_LIMIT_ROUTES", setRoutes(&c.RateLimit.Routes)},
+		{"rate_limit.addr", "RATE_LIMIT_ADDR", setLimit(&c.RateLimit.Addr)},
+
+		{"tracing.exporter", "TRACE_EXPORTER", setString(&c.Tracing.Exporter)},
+		{"tracing.endpoint", "TRACE_ENDPOINT", setString(&c.Tracing.Endpoint)},
+		{"tracing.service_name", "TRACE_SERVICE_NAME", setString(&c.Tracing.ServiceName)},
+		{"tracing.sample_ratio", "TRACE_SAMPLE_RATIO", setFloat(&c.Tracing.SampleRatio)},
+	}
+}
+
+// Load merges defaults, config file, environment variables and flags, every next source overrides previous
+// Config file is set by -config flag or CONFIG_FILE variable, format is chosen by extension: yaml, yml or toml
+// Empty environment variables are ignored. Returns arguments left after flags and all errors of config at once
+func Load(args []string) (*Config, []string, error) {
+	cfg := Default()
+	settings := cfg.settings()
+
+	var (
+		errs     []error
+		file     = os.Getenv(envConfigFile)
+		flagVals []func() error
+	)
+
+	fs := flag.NewFlagSet("songs", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	fs.StringVar(&file, "config", file, "path to yaml or toml config file")
+
+	// Flags are applied after file and environment, so their values are only remembered while parsing
+	for _, s := range settings {
+		fs.Func(s.flagName(), "overrides "+s.env, func(val string) error {
+			flagVals = append(flagVals, func() error {
+				return wrap("flag -"+s.flagName(), s.set(val))
+			})
+
+			return nil
+		})
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	if file != "" {
+		errs = append(errs, cfg.loadFile(file, settings)...)
+	}
+
+	for _, s := range settings {
+		if val := os.Getenv(s.env); val != "" {
+			errs = append(errs, wrap("env "+s.env, s.set(val)))
+		}
+	}
+
+	for _, set := range flagVals {
+		errs = append(errs, set())
+	}
+
+	errs = append(errs, cfg.Validate())
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, nil, fmt.Errorf("invalid config:\n%w", err)
+	}
+
+	return cfg, fs.Args(), nil
+}
+
+// loadFile sets values from yaml or toml file
+func (c *Config) loadFile(path string, settings []setting) []error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return []error{fmt.Errorf("can't read config file: %w", err)}
+	}
+
+	values := make(map[string]any)
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".toml":
+		err = toml.Unmarshal(data, &values)
+	default:
+		return []error{fmt.Errorf("unknown format of config file %q, must be yaml, yml or toml", ext)}
+	}
+
+	if err != nil {
+		return []error{fmt.Errorf("can't parse config file: %w", err)}
+	}
+
+	keys := make(map[string]setting, len(settings))
+	for _, s := range settings {
+		keys[s.key] = s
+	}
+
+	var errs []error
+
+	flat := make(map[string]string)
+	flatten("", values, keys, flat, &errs)
+
+	for key, val := range flat {
+		errs = append(errs, wrap("file key "+key, keys[key].set(val)))
+	}
+
+	return errs
+}
+
+// flatten converts nested values of file into values of settings by keys joined with dots
+// Tables of route limits are converted into format of RATE_LIMIT_ROUTES
+func flatten(prefix string, val any, keys map[string]setting, res map[string]string, errs *[]error) {
+	if table, ok := val.(map[string]any); ok {
+		if _, known := keys[prefix]; known {
+			entries := make([]string, 0, len(table))
+			for k, v := range table {
+				entries = append(entries, fmt.Sprintf("%s=%v", k, v))
+			}
+
+			slices.Sort(entries)
+
+			res[prefix] = strings.Join(entries, ";")
+			return
+		}
+
+		for k, v := range table {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+
+			flatten(key, v, keys, res, errs)
+		}
+
+		return
+	}
+
+	if _, known := keys[prefix]; !known {
+		*errs = append(*errs, fmt.Errorf("unknown config file key %q", prefix))
+		return
+	}
+
+	res[prefix] = fmt.Sprint(val)
+}
+
+// wrap adds source of value to error
+func wrap(source string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("%s: %w", source, err)
+}
+
+func setString(p *string) func(string) error {
+	return func(val string) error {
+		*p = val
+		return nil
+	}
+}
+
+func setInt(p *int) func(string) error {
+	return func(val string) error {
+		n, err := strconv.Atoi(val)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", val)
+		}
+
+		*p = n
+		return nil
+	}
+}
+
+func setBool(p *bool) func(string) error {
+	return func(val string) error {
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return fmt.Errorf("invalid bool %q, must be true or false", val)
+		}
+
+		*p = b
+		return nil
+	}
+}
+
+func setFloat(p *float64) func(string) error {
+	return func(val string) error {
+		f, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", val)
+		}
+
+		*p = f
+		return nil
+	}
+}
+
+func setDuration(p *time.Duration) func(string) error {
+	return func(val string) error {
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q, must be like 30s or 1m", val)
+		}
+
+		*p = d
+		return nil
+	}
+}
+
+func setLimit(p *Limit) func(string) error {
+	return func(val string) error {
+		limit, err := ParseLimit(val)
+		if err != nil {
+			return err
+		}
+
+		*p = limit
+		return nil
+	}
+}
+
+// setRoutes parses limits of routes separated by semicolon, e.g. POST /songs=10/m;GET /songs=100/m
+func setRoutes(p *map[string]Limit) func(string) error {
+	return func(val string) error {
+		routes := make(map[string]Limit)
+
+		for _, entry := range strings.Split(val, ";") {
+			if strings.TrimSpace(entry) == "" {
+				continue
+			}
+
+			route, rate, ok := strings.Cut(entry, "=")
+			if !ok {
+				return fmt.Errorf("invalid route limit %q, must be in format route=count/unit", entry)
+			}
+
+			limit, err := ParseLimit(rate)
+			if err != nil {
+				return err
+			}
+
+			routes[strings.TrimSpace(route)] = limit
+		}
+
+		*p = routes
+		return nil
+	}
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+)
+
+// Validate checks all values of config and returns all found errors at once
+func (c *Config) Validate() error {
+	var errs []error
+
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	if _, err := logger.ParseLevel(c.Level); err != nil {
+		errs = append(errs, err)
+	}
+
+	check(logger.IsFormat(c.Log.Format), "unknown log format %q, must be text or json", c.Log.Format)
+
+	for name, n := range map[string]int{
+		"log max size":          c.Log.MaxSize,
+		"log max backups":       c.Log.MaxBackups,
+		"log max age":           c.Log.MaxAge,
+		"log max field len":     c.Log.MaxFieldLen,
+		"log max items":         c.Log.MaxItems,
+		"log sample first":      c.Log.SampleFirst,
+		"log sample thereafter": c.Log.SampleThereafter,
+	} {
+		check(n >= 0, "invalid %s %d, must be non-negative", name, n)
+	}
+
+	check(c.DB.Host != "", "db host is required")
+	check(validPort(c.DB.Port), "invalid db port %q, must be from 1 to 65535", c.DB.Port)
+	check(c.DB.User != "", "db user is required")
+	check(c.DB.Name != "", "db name is required")
+
+	check(c.API.Host != "", "api host is required")
+	check(validPort(c.API.Port), "invalid api port %q, must be from 1 to 65535", c.API.Port)
+	check(c.API.Concurrency > 0, "invalid api concurrency %d, must be positive", c.API.Concurrency)
+
+	check(validPort(c.Server.Port), "invalid server port %q, must be from 1 to 65535", c.Server.Port)
+	check(!c.Server.GRPCEnabled || validPort(c.Server.GRPCPort), "invalid server grpc port %q, must be from 1 to 65535", c.Server.GRPCPort)
+	check(!c.Server.GRPCEnabled || c.Server.GRPCPort != c.Server.Port, "server grpc port must differ from server port")
+	check(c.Server.Timeout > 0, "invalid server timeout %s, must be positive", c.Server.Timeout)
+	check(c.Server.IdleTimeout > 0, "invalid idle timeout %s, must be positive", c.Server.IdleTimeout)
+	check(c.Server.ShutdownTimeout > 0, "invalid shutdown timeout %s, must be positive", c.Server.ShutdownTimeout)
+	check(c.Server.DrainDelay >= 0 && c.Server.DrainDelay < c.Server.ShutdownTimeout,
+		"invalid drain delay %s, must be from 0 to shutdown timeout %s", c.Server.DrainDelay, c.Server.ShutdownTimeout)
+
+	check((c.Server.TLSCert == "") == (c.Server.TLSKey == ""), "server tls cert and key must be set together")
+	check(c.Server.TLSClientCA == "" || c.Server.TLSEnabled(), "server tls client ca requires tls cert and key")
+	check(c.Server.TLSClientAuth == "" || c.Server.TLSClientAuth == "optional" || c.Server.TLSClientAuth == "require",
+		"unknown server tls client auth %q, must be optional or require", c.Server.TLSClientAuth)
+
+	check((c.API.TLSCert == "") == (c.API.TLSKey == ""), "api tls cert and key must be set together")
+	check(c.API.TLS || c.API.TLSCA == "" && c.API.TLSCert == "", "api tls ca and cert require api tls")
+
+	check(c.JWT.Leeway >= 0, "invalid jwt leeway %s, must be non-negative", c.JWT.Leeway)
+
+	switch c.Tracing.Exporter {
+	case ExporterNone, ExporterOTLP, ExporterStdout:
+	default:
+		errs = append(errs, fmt.Errorf("unknown trace exporter %q, must be otlp or stdout", c.Tracing.Exporter))
+	}
+
+	check(c.Tracing.Exporter != ExporterOTLP || c.Tracing.Endpoint != "", "trace endpoint is required for otlp exporter")
+	check(c.Tracing.ServiceName != "", "trace service name is required")
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "invalid trace sample ratio %g, must be from 0 to 1", c.Tracing.SampleRatio)
+
+	return errors.Join(errs...)
+}
+
+// validPort checks that port is a number of tcp port
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n <= 65535
+}
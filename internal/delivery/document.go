@@ -0,0 +1,75 @@
+package delivery
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/internal/service"
+)
+
+// type document represents result that could be sent as plain text or markdown
+type document interface {
+	Text() string
+	Markdown() (string, error)
+}
+
+// markdownEscaper escapes characters that have special meaning in markdown
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"#", `\#`, "<", `\<`, ">", `\>`, "|", `\|`,
+)
+
+// type versesDocument represents verses of song as lyrics
+// Song details are got only for markdown
+type versesDocument struct {
+	ctx     context.Context
+	service service.ServiceIface
+	id      int
+	verses  []string
+}
+
+// Text returns verses separated by empty lines
+func (v *versesDocument) Text() string {
+	return strings.Join(v.verses, "\n\n") + "\n"
+}
+
+// Markdown returns page with song title, group, release date, link and verses
+func (v *versesDocument) Markdown() (string, error) {
+	songs, err := v.service.GetAll(v.ctx, models.GetFilters{Limit: 1, Id: v.id})
+	if err != nil {
+		return "", err
+	}
+
+	if len(songs) < 1 {
+		return "", fmt.Errorf("song %d not found", v.id)
+	}
+
+	song := songs[0]
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", markdownEscaper.Replace(song.Song))
+	fmt.Fprintf(&b, "**Group:** %s\n", markdownEscaper.Replace(song.Group))
+
+	if song.Date != "" {
+		fmt.Fprintf(&b, "\n**Released:** %s\n", markdownEscaper.Replace(song.Date))
+	}
+
+	if song.Link != "" {
+		fmt.Fprintf(&b, "\n**Link:** <%s>\n", song.Link)
+	}
+
+	for _, verse := range v.verses {
+		lines := strings.Split(verse, "\n")
+		for i := range lines {
+			lines[i] = markdownEscaper.Replace(lines[i])
+		}
+
+		// Two trailing spaces keep line breaks inside paragraph
+		fmt.Fprintf(&b, "\n%s\n", strings.Join(lines, "  \n"))
+	}
+
+	return b.String(), nil
+}
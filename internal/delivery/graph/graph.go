@@ -0,0 +1,143 @@
+package graph
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/internal/service"
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+)
+
+//go:embed schema.graphql
+var schema string
+
+const (
+	// maxDepth limits nesting of queries
+	maxDepth = 10
+	// maxBodySize limits size of request body
+	maxBodySize = 1 << 20
+	// loaderWait is the time that loader waits for more keys before fetch
+	loaderWait = 2 * time.Millisecond
+	// loaderMaxBatch is the maximum count of songs fetched at once, the same as max batch of REST API
+	loaderMaxBatch = models.MaxBatchSize
+)
+
+// loaderCtx is the context key of songs loader
+type loaderCtx struct{}
+
+// type Request represents body of GraphQL request
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+type Handler struct {
+	log     *slog.Logger
+	service service.ServiceIface
+	schema  *graphql.Schema
+}
+
+func NewHandler(l *slog.Logger, s service.ServiceIface) (*Handler, error) {
+	h := &Handler{
+		log:     l,
+		service: s,
+	}
+
+	parsed, err := graphql.ParseSchema(schema, &resolver{service: s},
+		graphql.MaxDepth(maxDepth),
+		graphql.PanicHandler(&panicHandler{}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse graphql schema: %w", err)
+	}
+
+	h.schema = parsed
+
+	return h, nil
+}
+
+// Query executes GraphQL query or mutation
+// @Summary GraphQL endpoint
+// @Description Executes query or mutation with selection of song fields, verses are paginated sub-field of song. Mutations require write role
+// @Tags graphql
+// @Accept  json
+// @Produce  json
+// @Param request body graph.Request true "GraphQL request"
+// @Success 200 {object} object "Data and errors of query"
+// @Failure 400 {object} object "Errors of invalid request"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /graphql [post]
+func (h *Handler) Query(w http.ResponseWriter, r *http.Request) {
+	var req Request
+
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&req); err != nil || req.Query == "" {
+		h.respond(w, r, &graphql.Response{Errors: []*gqlerrors.QueryError{gqlerrors.Errorf("Can't decode graphql request")}}, http.StatusBadRequest)
+		return
+	}
+
+	ctx := logger.NewCtxWithLog(r.Context(), h.log)
+
+	// Loader lives as long as request, so songs are never cached between requests
+	ctx = context.WithValue(ctx, loaderCtx{}, NewLoader(loaderWait, loaderMaxBatch, func(ids []int) (map[int]models.Song, error) {
+		songs, err := h.service.GetAll(ctx, models.GetFilters{Ids: ids, Limit: len(ids)})
+		if err != nil {
+			return nil, err
+		}
+
+		res := make(map[int]models.Song, len(songs))
+		for _, song := range songs {
+			res[song.Id] = song
+		}
+
+		return res, nil
+	}))
+
+	res := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+
+	h.respond(w, r, res, http.StatusOK)
+}
+
+// type panicHandler logs panics of resolvers with logger of request
+type panicHandler struct{}
+
+// MakePanicError logs panic of resolver and returns error without details
+func (p *panicHandler) MakePanicError(ctx context.Context, value any) *gqlerrors.QueryError {
+	logger.LogUse(ctx).Error("Panic in graphql resolver", slog.Any("panic", value), slog.String("stack", string(debug.Stack())))
+
+	return gqlerrors.Errorf("Internal error")
+}
+
+// respond sends GraphQL response and logs it
+func (h *Handler) respond(w http.ResponseWriter, r *http.Request, res *graphql.Response, status int) {
+	log := logger.LogWith(r.Context(), h.log)
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		log.Error("Can't marshal response: " + err.Error())
+
+		data, _ = json.Marshal(&graphql.Response{Errors: []*gqlerrors.QueryError{gqlerrors.Errorf("Can't marshal response")}})
+		status = http.StatusInternalServerError
+	}
+
+	log.Info("Response", slog.Int("errors", len(res.Errors)), slog.Int("size", len(data)), slog.Int("status", status))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// songLoader returns loader of songs from request context
+func songLoader(ctx context.Context) *Loader[int, models.Song] {
+	return ctx.Value(loaderCtx{}).(*Loader[int, models.Song])
+}
@@ -0,0 +1,175 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/s3nn1k/ef-mob-task/internal/delivery/middleware"
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/internal/service/mocks"
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+// query sends GraphQL request to handler and returns status and body
+func query(t *testing.T, h *Handler, ctx context.Context, body string) (int, string) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body)).WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	h.Query(rec, req)
+
+	return rec.Code, strings.TrimSpace(rec.Body.String())
+}
+
+// gql encodes GraphQL request body
+func gql(t *testing.T, q string) string {
+	t.Helper()
+
+	data, err := json.Marshal(Request{Query: q})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func newHandler(t *testing.T, mock *mocks.ServiceIface) *Handler {
+	t.Helper()
+
+	h, err := NewHandler(logger.NewTextLogger(""), mock)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return h
+}
+
+func TestSongsWithVerses(t *testing.T) {
+	mock := mocks.NewServiceIface(t)
+
+	songs := []models.Song{
+		{Id: 1, Song: "First", Text: "One\n\nTwo"},
+		{Id: 2, Song: "Second", Text: "Three"},
+		{Id: 3, Song: "Third", Text: "Four"},
+	}
+
+	mock.On("GetAll", testifymock.Anything, models.GetFilters{Limit: 3, Group: "TestGroup"}).Return(songs, nil).Once()
+
+	for _, song := range songs {
+		verses := strings.Split(song.Text, "\n\n")
+
+		mock.On("SongVerses", testifymock.Anything, song, models.GetVersesFilters{Id: song.Id, Limit: 2}).Return(verses, nil).Once()
+	}
+
+	status, body := query(t, newHandler(t, mock), context.Background(),
+		gql(t, `{ songs(filter: {group: "TestGroup"}, limit: 3) { id song verses(limit: 1) { items hasNext } } }`))
+
+	want := `{"data":{"songs":[` +
+		`{"id":1,"song":"First","verses":{"items":["One"],"hasNext":true}},` +
+		`{"id":2,"song":"Second","verses":{"items":["Three"],"hasNext":false}},` +
+		`{"id":3,"song":"Third","verses":{"items":["Four"],"hasNext":false}}]}}`
+
+	if status != http.StatusOK || body != want {
+		t.Fatalf("error: want %d %s, but got %d %s", http.StatusOK, want, status, body)
+	}
+
+	// Verses of listed songs must not load songs again
+	mock.AssertNumberOfCalls(t, "GetAll", 1)
+}
+
+func TestSongBatching(t *testing.T) {
+	mock := mocks.NewServiceIface(t)
+
+	mock.On("GetAll", testifymock.Anything, testifymock.MatchedBy(func(filters models.GetFilters) bool {
+		ids := slices.Clone(filters.Ids)
+		slices.Sort(ids)
+
+		return slices.Equal(ids, []int{1, 2, 3}) && filters.Limit == 3
+	})).Return([]models.Song{{Id: 1, Song: "First"}, {Id: 3, Song: "Third"}}, nil).Once()
+
+	status, body := query(t, newHandler(t, mock), context.Background(),
+		gql(t, `{ a: song(id: 1) { song } b: song(id: 2) { song } c: song(id: 3) { song } again: song(id: 1) { id } }`))
+
+	want := `{"data":{"a":{"song":"First"},"b":null,"c":{"song":"Third"},"again":{"id":1}}}`
+
+	if status != http.StatusOK || body != want {
+		t.Fatalf("error: want %d %s, but got %d %s", http.StatusOK, want, status, body)
+	}
+}
+
+func TestMutations(t *testing.T) {
+	mock := mocks.NewServiceIface(t)
+
+	song := models.Song{Id: 1, Song: "TestSong", Group: "TestGroup", Text: "Text", Link: "Link", Date: "16.07.2006"}
+
+	mock.On("Create", testifymock.Anything, "TestSong", "TestGroup").Return(song, nil).Once()
+	mock.On("Update", testifymock.Anything, song).Return(false, nil).Once()
+	mock.On("Delete", testifymock.Anything, 1).Return(true, nil).Once()
+
+	h := newHandler(t, mock)
+
+	writer := middleware.NewCtxWithPrincipal(context.Background(), models.Principal{Roles: []string{models.ScopeWrite}})
+	reader := middleware.NewCtxWithPrincipal(context.Background(), models.Principal{Roles: []string{models.ScopeRead}})
+
+	testCases := []struct {
+		name string
+		ctx  context.Context
+		q    string
+		want string
+	}{
+		{
+			name: "create",
+			ctx:  writer,
+			q:    `mutation { createSong(song: "TestSong", group: "TestGroup") { id releaseDate } }`,
+			want: `{"data":{"createSong":{"id":1,"releaseDate":"16.07.2006"}}}`,
+		},
+		{
+			name: "update not exists",
+			ctx:  writer,
+			q:    `mutation { updateSong(id: 1, input: {song: "TestSong", group: "TestGroup", text: "Text", link: "Link", releaseDate: "16.07.2006"}) { id } }`,
+			want: `{"data":{"updateSong":null}}`,
+		},
+		{
+			name: "delete without auth",
+			ctx:  context.Background(),
+			q:    `mutation { deleteSong(id: 1) }`,
+			want: `{"data":{"deleteSong":true}}`,
+		},
+		{
+			name: "forbidden",
+			ctx:  reader,
+			q:    `mutation { deleteSong(id: 1) }`,
+			want: `{"errors":[{"message":"Role write is required","path":["deleteSong"]}],"data":null}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status, body := query(t, h, tc.ctx, gql(t, tc.q))
+			if status != http.StatusOK || body != tc.want {
+				t.Fatalf("error: want %s, but got %d %s", tc.want, status, body)
+			}
+		})
+	}
+}
+
+func TestInvalidRequest(t *testing.T) {
+	h := newHandler(t, mocks.NewServiceIface(t))
+
+	status, body := query(t, h, context.Background(), `{"query":`)
+	if status != http.StatusBadRequest || body != `{"errors":[{"message":"Can't decode graphql request"}]}` {
+		t.Fatalf("error: want bad request, but got %d %s", status, body)
+	}
+
+	status, body = query(t, h, context.Background(), gql(t, `{ songs { unknown } }`))
+	if status != http.StatusOK || !strings.Contains(body, `Cannot query field \"unknown\"`) {
+		t.Fatalf("error: want validation error, but got %d %s", status, body)
+	}
+}
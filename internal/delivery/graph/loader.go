@@ -0,0 +1,133 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// type Loader batches loads of keys, that are requested concurrently, into one fetch
+// Values are cached for the lifetime of loader, so it's created for every request
+type Loader[K comparable, V any] struct {
+	fetch    func(keys []K) (map[K]V, error)
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	cache   map[K]*result[V]
+	current *batch[K]
+}
+
+// type result is the value of key, done is closed when it's fetched
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	ok    bool
+	err   error
+}
+
+// type batch represents keys that are fetched at once
+type batch[K comparable] struct {
+	keys []K
+	sent bool
+}
+
+// NewLoader creates loader, that waits for more keys before fetch until batch is full
+// Fetch returns values of found keys
+func NewLoader[K comparable, V any](wait time.Duration, maxBatch int, fetch func(keys []K) (map[K]V, error)) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		cache:    map[K]*result[V]{},
+	}
+}
+
+// Prime caches already known value, so it's not fetched
+func (l *Loader[K, V]) Prime(key K, value V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.cache[key]; ok {
+		return
+	}
+
+	res := &result[V]{done: make(chan struct{}), value: value, ok: true}
+	close(res.done)
+
+	l.cache[key] = res
+}
+
+// Load returns value of key, ok is false if it's not found
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, bool, error) {
+	l.mu.Lock()
+
+	res, ok := l.cache[key]
+	if !ok {
+		res = &result[V]{done: make(chan struct{})}
+		l.cache[key] = res
+
+		if l.current == nil {
+			b := &batch[K]{}
+			l.current = b
+
+			time.AfterFunc(l.wait, func() { l.dispatch(b) })
+		}
+
+		l.current.keys = append(l.current.keys, key)
+
+		// Full batch is detached, so next keys start a new one
+		if len(l.current.keys) >= l.maxBatch {
+			go l.dispatch(l.current)
+
+			l.current = nil
+		}
+	}
+
+	l.mu.Unlock()
+
+	select {
+	case <-res.done:
+		return res.value, res.ok, res.err
+	case <-ctx.Done():
+		var zero V
+		return zero, false, ctx.Err()
+	}
+}
+
+// dispatch fetches keys of batch, if they weren't fetched yet
+func (l *Loader[K, V]) dispatch(b *batch[K]) {
+	l.mu.Lock()
+
+	if b.sent {
+		l.mu.Unlock()
+		return
+	}
+
+	b.sent = true
+
+	if l.current == b {
+		l.current = nil
+	}
+
+	results := make([]*result[V], 0, len(b.keys))
+	for _, key := range b.keys {
+		results = append(results, l.cache[key])
+	}
+
+	l.mu.Unlock()
+
+	values, err := l.fetch(b.keys)
+
+	for i, key := range b.keys {
+		res := results[i]
+
+		if err != nil {
+			res.err = err
+		} else {
+			res.value, res.ok = values[key]
+		}
+
+		close(res.done)
+	}
+}
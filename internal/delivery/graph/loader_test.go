@@ -0,0 +1,124 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// type fetcher records keys of every fetch
+type fetcher struct {
+	mu      sync.Mutex
+	batches [][]int
+	err     error
+}
+
+func (f *fetcher) fetch(keys []int) (map[int]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.batches = append(f.batches, keys)
+
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	res := map[int]string{}
+	for _, key := range keys {
+		// Odd keys are not found
+		if key%2 == 0 {
+			res[key] = "value"
+		}
+	}
+
+	return res, nil
+}
+
+// loadAll loads keys concurrently and returns count of found ones
+func loadAll(t *testing.T, l *Loader[int, string], keys ...int) (int, error) {
+	t.Helper()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	found := 0
+	var loadErr error
+
+	for _, key := range keys {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, ok, err := l.Load(context.Background(), key)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				loadErr = err
+			}
+
+			if ok {
+				found++
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return found, loadErr
+}
+
+func TestLoader(t *testing.T) {
+	f := &fetcher{}
+	l := NewLoader(10*time.Millisecond, 3, f.fetch)
+
+	l.Prime(10, "primed")
+
+	found, err := loadAll(t, l, 1, 2, 2, 4, 5, 6, 10)
+	if err != nil {
+		t.Fatalf("error not expected while loading: %s", err)
+	}
+
+	// 2 is loaded twice, 10 is primed
+	if found != 5 {
+		t.Fatalf("error: want 5 found keys, but got %d", found)
+	}
+
+	count := 0
+	for _, batch := range f.batches {
+		if len(batch) > 3 {
+			t.Fatalf("error: batch must be limited by 3 keys, but got %v", batch)
+		}
+
+		count += len(batch)
+	}
+
+	if count != 5 || len(f.batches) != 2 {
+		t.Fatalf("error: want 5 unique keys fetched in 2 batches, but got %v", f.batches)
+	}
+
+	// Cached keys are not fetched again
+	if found, _ := loadAll(t, l, 1, 2); found != 1 || len(f.batches) != 2 {
+		t.Fatalf("error: want cached keys, but got %d found and batches %v", found, f.batches)
+	}
+}
+
+func TestLoaderError(t *testing.T) {
+	f := &fetcher{err: errors.New("storage is down")}
+	l := NewLoader(time.Millisecond, 10, f.fetch)
+
+	if _, err := loadAll(t, l, 1, 2); !errors.Is(err, f.err) {
+		t.Fatalf("error: want error of fetch, but got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, _, err := NewLoader(time.Hour, 10, f.fetch).Load(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("error: want context error, but got %v", err)
+	}
+}
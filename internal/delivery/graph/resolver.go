@@ -0,0 +1,310 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/s3nn1k/ef-mob-task/internal/delivery/middleware"
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/internal/service"
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+)
+
+// defaultLimit is the page size if limit is not set, the same as in REST API
+const defaultLimit = 10
+
+// type resolver resolves queries and mutations of schema
+// Errors of service are logged with logger of request and replaced by the same messages as in REST API
+type resolver struct {
+	service service.ServiceIface
+}
+
+type songFilter struct {
+	Id          *int32
+	Song        *string
+	Group       *string
+	ReleaseDate *string
+}
+
+type songInput struct {
+	Song        string
+	Group       string
+	Text        string
+	Link        string
+	ReleaseDate string
+}
+
+func (r *resolver) Song(ctx context.Context, args struct{ Id int32 }) (*songResolver, error) {
+	song, ok, err := songLoader(ctx).Load(ctx, int(args.Id))
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), slog.Int("id", int(args.Id)))
+
+		return nil, errors.New("Can't get song")
+	}
+
+	if !ok {
+		return nil, nil
+	}
+
+	return &songResolver{r: r, song: song}, nil
+}
+
+func (r *resolver) Songs(ctx context.Context, args struct {
+	Filter *songFilter
+	Limit  int32
+	Offset int32
+}) ([]*songResolver, error) {
+	filters := models.GetFilters{
+		Limit:  int(args.Limit),
+		Offset: int(args.Offset),
+	}
+
+	if filters.Limit < 1 {
+		filters.Limit = defaultLimit
+	}
+
+	if filters.Offset < 1 {
+		filters.Offset = 0
+	}
+
+	if f := args.Filter; f != nil {
+		filters.Id = intOr(f.Id, 0)
+		filters.Song = stringOr(f.Song)
+		filters.Group = stringOr(f.Group)
+		filters.Date = stringOr(f.ReleaseDate)
+	}
+
+	songs, err := r.service.GetAll(ctx, filters)
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), "input", slog.Any("filters", filters.LogValue()))
+
+		return nil, errors.New("Can't get songs")
+	}
+
+	// Songs are cached, so their verses are resolved without loading them again
+	loader := songLoader(ctx)
+
+	res := make([]*songResolver, 0, len(songs))
+	for _, song := range songs {
+		loader.Prime(song.Id, song)
+
+		res = append(res, &songResolver{r: r, song: song})
+	}
+
+	return res, nil
+}
+
+func (r *resolver) CreateSong(ctx context.Context, args struct {
+	Song  string
+	Group string
+}) (*songResolver, error) {
+	if err := requireRole(ctx, models.ScopeWrite); err != nil {
+		return nil, err
+	}
+
+	song, err := r.service.Create(ctx, args.Song, args.Group)
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), slog.String("song", args.Song), slog.String("group", args.Group))
+
+		return nil, errors.New("Can't create song")
+	}
+
+	songLoader(ctx).Prime(song.Id, song)
+
+	return &songResolver{r: r, song: song}, nil
+}
+
+func (r *resolver) UpdateSong(ctx context.Context, args struct {
+	Id    int32
+	Input songInput
+}) (*songResolver, error) {
+	if err := requireRole(ctx, models.ScopeWrite); err != nil {
+		return nil, err
+	}
+
+	song := models.Song{
+		Id:    int(args.Id),
+		Song:  args.Input.Song,
+		Group: args.Input.Group,
+		Text:  args.Input.Text,
+		Link:  args.Input.Link,
+		Date:  args.Input.ReleaseDate,
+	}
+
+	ok, err := r.service.Update(ctx, song)
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), "input", song.LogValue())
+
+		return nil, errors.New("Can't update song")
+	}
+
+	if !ok {
+		return nil, nil
+	}
+
+	songLoader(ctx).Prime(song.Id, song)
+
+	return &songResolver{r: r, song: song}, nil
+}
+
+func (r *resolver) DeleteSong(ctx context.Context, args struct{ Id int32 }) (bool, error) {
+	if err := requireRole(ctx, models.ScopeWrite); err != nil {
+		return false, err
+	}
+
+	ok, err := r.service.Delete(ctx, int(args.Id))
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), slog.Int("id", int(args.Id)))
+
+		return false, errors.New("Can't delete song")
+	}
+
+	return ok, nil
+}
+
+// type songResolver resolves fields of song
+type songResolver struct {
+	r    *resolver
+	song models.Song
+}
+
+func (s *songResolver) Id() int32 {
+	return int32(s.song.Id)
+}
+
+func (s *songResolver) Song() string {
+	return s.song.Song
+}
+
+func (s *songResolver) Group() string {
+	return s.song.Group
+}
+
+func (s *songResolver) Text() string {
+	return s.song.Text
+}
+
+func (s *songResolver) Link() string {
+	return s.song.Link
+}
+
+func (s *songResolver) ReleaseDate() string {
+	return s.song.Date
+}
+
+// Verses returns page of verses by the same rules as GetVerses
+// Song is taken from loader, so verses of all songs in list cost at most one storage call
+func (s *songResolver) Verses(ctx context.Context, args struct {
+	Limit  int32
+	Offset int32
+	Type   *string
+	Lang   *string
+}) (*versePage, error) {
+	filters := models.GetVersesFilters{
+		Id:     s.song.Id,
+		Limit:  int(args.Limit),
+		Offset: int(args.Offset),
+		Type:   stringOr(args.Type),
+	}
+
+	if filters.Limit < 1 {
+		filters.Limit = defaultLimit
+	}
+
+	if filters.Offset < 1 {
+		filters.Offset = 0
+	}
+
+	if filters.Type != "" && !models.IsSectionType(filters.Type) {
+		return nil, errors.New("Unknown section type")
+	}
+
+	if lang := stringOr(args.Lang); lang != "" {
+		lang, err := models.ParseLang(lang)
+		if err != nil {
+			return nil, errors.New("lang must be valid language tag")
+		}
+
+		filters.Lang = lang
+	}
+
+	song, ok, err := songLoader(ctx).Load(ctx, s.song.Id)
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), "input", slog.Any("filters", filters.LogValue()))
+
+		return nil, errors.New("Can't get song")
+	}
+
+	if !ok {
+		return &versePage{offset: filters.Offset, items: []string{}}, nil
+	}
+
+	// One more verse is requested to know that next page exists
+	limit := filters.Limit
+	filters.Limit++
+
+	verses, err := s.r.service.SongVerses(ctx, song, filters)
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), "input", slog.Any("filters", filters.LogValue()))
+
+		return nil, errors.New("Can't get song")
+	}
+
+	page := &versePage{offset: filters.Offset, items: verses}
+
+	if len(verses) > limit {
+		page.items = verses[:limit]
+		page.hasNext = true
+	}
+
+	return page, nil
+}
+
+// type versePage resolves page of verses
+type versePage struct {
+	items   []string
+	offset  int
+	hasNext bool
+}
+
+func (p *versePage) Items() []string {
+	return p.items
+}
+
+func (p *versePage) Offset() int32 {
+	return int32(p.offset)
+}
+
+func (p *versePage) HasNext() bool {
+	return p.hasNext
+}
+
+// requireRole checks that principal of request has the given role
+// Request is allowed if auth is disabled, so principal is not set
+func requireRole(ctx context.Context, role string) error {
+	principal, ok := middleware.PrincipalFromContext(ctx)
+	if !ok || principal.HasRole(role) {
+		return nil
+	}
+
+	logger.LogUse(ctx).Warn("Forbidden", "principal", principal.LogValue(), slog.String("required", role))
+
+	return errors.New("Role " + role + " is required")
+}
+
+func intOr(val *int32, def int) int {
+	if val == nil {
+		return def
+	}
+
+	return int(*val)
+}
+
+func stringOr(val *string) string {
+	if val == nil {
+		return ""
+	}
+
+	return *val
+}
@@ -1,15 +1,30 @@
 package delivery
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/s3nn1k/ef-mob-task/internal/models"
 	"github.com/s3nn1k/ef-mob-task/internal/service"
 	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+	"github.com/s3nn1k/ef-mob-task/pkg/lrc"
 )
 
+// Available formats of synchronized lyrics
+const (
+	formatJson = "json"
+	formatLrc  = "lrc"
+)
+
+// maxLyricsSize limits size of uploaded LRC file
+const maxLyricsSize = 1 << 20
+
 type Handler struct {
 	log     *slog.Logger
 	service service.ServiceIface
@@ -32,12 +47,14 @@ func NewHandler(l *slog.Logger, s service.ServiceIface) *Handler {
 // @Success 200 {object} models.Song "Created song"
 // @Failure 400 {object} Response "Invalid input"
 // @Failure 500 {object} Response "Failed to create song"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /songs [post]
 func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	var song models.Song
 
 	if err := json.NewDecoder(r.Body).Decode(&song); err != nil {
-		h.response(w, Error("Can't decode json body"), http.StatusBadRequest)
+		h.response(w, r, Error("Can't decode json body"), http.StatusBadRequest)
 		return
 	}
 
@@ -45,13 +62,108 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 
 	song, err := h.service.Create(ctx, song.Song, song.Group)
 	if err != nil {
-		h.log.Error(err.Error(), "input", song.AsLogValue())
+		logger.LogUse(ctx).Error(err.Error(), "input", song.LogValue())
+
+		h.response(w, r, Error("Can't create song"), http.StatusInternalServerError)
+		return
+	}
+
+	h.response(w, r, Ok([]models.Song{song}), http.StatusOK)
+}
+
+// CreateBatch creates many songs at once
+// @Summary Create many songs
+// @Description Gets details of songs concurrently and creates all found songs at once, returns result for every song
+// @Tags songs
+// @Accept  json
+// @Produce  json
+// @Param songs body []models.Song true "Songs with song and group names"
+// @Success 200 {array} models.BatchResult "Result for every song in the same order"
+// @Failure 400 {object} Response "Invalid input"
+// @Failure 500 {object} Response "Failed to create songs"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /songs:batch [post]
+func (h *Handler) CreateBatch(w http.ResponseWriter, r *http.Request) {
+	var songs []models.Song
+
+	if err := json.NewDecoder(r.Body).Decode(&songs); err != nil {
+		h.response(w, r, Error("Can't decode json body"), http.StatusBadRequest)
+		return
+	}
+
+	if len(songs) == 0 || len(songs) > models.MaxBatchSize {
+		h.response(w, r, Error(fmt.Sprintf("count of songs must be from 1 to %d", models.MaxBatchSize)), http.StatusBadRequest)
+		return
+	}
+
+	ctx := logger.NewCtxWithLog(r.Context(), h.log)
+
+	results, err := h.service.CreateBatch(ctx, songs)
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), "input", slog.Int("count", len(songs)))
+
+		h.response(w, r, Error("Can't create songs"), http.StatusInternalServerError)
+		return
+	}
+
+	h.response(w, r, Ok(results), http.StatusOK)
+}
+
+// Import creates songs from csv or jsonl file
+// @Summary Import songs
+// @Description Reads songs from csv file with header or jsonl file, validates them and creates in batches. Invalid rows don't stop the import and are returned in report
+// @Tags songs
+// @Accept  plain
+// @Produce  json
+// @Param format query string false "Format of file, detected by Content-Type if empty" Enums(csv, jsonl)
+// @Param enrich query bool false "Get missing text, link and release date from API"
+// @Param file body string true "CSV or JSONL file"
+// @Success 200 {object} models.ImportReport "Import report"
+// @Failure 400 {object} Response "Invalid format or file"
+// @Failure 500 {object} Response "Failed to import songs"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /songs/import [post]
+func (h *Handler) Import(w http.ResponseWriter, r *http.Request) {
+	opts := models.ImportOptions{
+		Format: r.URL.Query().Get("format"),
+		Enrich: r.URL.Query().Get("enrich") == "true",
+	}
+
+	if opts.Format == "" {
+		switch mediaType(r.Header.Get("Content-Type")) {
+		case "text/csv":
+			opts.Format = models.FormatCsv
+		case "application/jsonl", "application/x-ndjson", "application/x-jsonlines":
+			opts.Format = models.FormatJsonl
+		}
+	}
+
+	if opts.Format != models.FormatCsv && opts.Format != models.FormatJsonl {
+		h.response(w, r, Error("format must be csv or jsonl"), http.StatusBadRequest)
+		return
+	}
+
+	// File could be read and imported longer than server timeout
+	clearDeadlines(w)
+
+	ctx := logger.NewCtxWithLog(r.Context(), h.log)
+
+	report, err := h.service.Import(ctx, r.Body, opts)
+	if errors.Is(err, service.ErrInvalidFile) {
+		h.response(w, r, Error("Can't import songs: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), "input", opts.LogValue())
 
-		h.response(w, Error("Can't create song"), http.StatusInternalServerError)
+		h.response(w, r, Error("Can't import songs"), http.StatusInternalServerError)
 		return
 	}
 
-	h.response(w, Ok([]models.Song{song}), http.StatusOK)
+	h.response(w, r, Ok([]models.ImportReport{report}), http.StatusOK)
 }
 
 // Update updates an existing song
@@ -66,17 +178,19 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 // @Failure 400 {object} Response "Invalid input"
 // @Failure 404 {object} Response "Song not found"
 // @Failure 500 {object} Response "Failed to update song"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /songs/{id} [put]
 func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	var song models.Song
 
 	if err := json.NewDecoder(r.Body).Decode(&song); err != nil {
-		h.response(w, Error("Can't decode json body"), http.StatusBadRequest)
+		h.response(w, r, Error("Can't decode json body"), http.StatusBadRequest)
 		return
 	}
 
 	if err := song.SetQueryId(r); err != nil {
-		h.response(w, Error("id must be int"), http.StatusBadRequest)
+		h.response(w, r, Error("id must be int"), http.StatusBadRequest)
 		return
 	}
 
@@ -84,18 +198,18 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 
 	ok, err := h.service.Update(ctx, song)
 	if err != nil {
-		h.log.Error(err.Error(), "input", song.AsLogValue())
+		logger.LogUse(ctx).Error(err.Error(), "input", song.LogValue())
 
-		h.response(w, Error("Can't update song"), http.StatusInternalServerError)
+		h.response(w, r, Error("Can't update song"), http.StatusInternalServerError)
 		return
 	}
 
 	if !ok {
-		h.response(w, Error("Song not exists"), http.StatusNotFound)
+		h.response(w, r, Error("Song not exists"), http.StatusNotFound)
 		return
 	}
 
-	h.response(w, Ok(nil), http.StatusOK)
+	h.response(w, r, Ok(nil), http.StatusOK)
 }
 
 // GetAll returns a list of Song's
@@ -109,15 +223,18 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 // @Param song query string false "Song title"
 // @Param group query string false "Group name"
 // @Param date query string false "Song release date in format 02.01.2006"
-// @Success 200 {array} models.Song "Array of Song's"
+// @Param fields query string false "Comma separated fields of songs, all fields by default" example(id,song,group)
+// @Success 200 {array} models.Song "Array of Song's with only selected fields"
 // @Failure 400 {object} Response "Invalid query parameters"
 // @Failure 500 {object} Response "Failed to get Song's"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /songs [get]
 func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
 	var filters models.GetFilters
 
 	if err := filters.SetQueryData(r); err != nil {
-		h.response(w, Error("limit, offset and id must be int"), http.StatusBadRequest)
+		h.response(w, r, Error(filtersError(err)), http.StatusBadRequest)
 		return
 	}
 
@@ -125,57 +242,432 @@ func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
 
 	songs, err := h.service.GetAll(ctx, filters)
 	if err != nil {
-		h.log.Error(err.Error(), "input", slog.Any("filters", filters.AsLogValue()))
+		logger.LogUse(ctx).Error(err.Error(), "input", slog.Any("filters", filters.LogValue()))
 
-		h.response(w, Error("Can't get songs"), http.StatusInternalServerError)
+		h.response(w, r, Error("Can't get songs"), http.StatusInternalServerError)
 		return
 	}
 
-	h.response(w, Ok(songs), http.StatusOK)
+	if len(filters.Fields) > 0 {
+		h.response(w, r, Ok(models.PartialSongs(songs, filters.Fields)), http.StatusOK)
+		return
+	}
+
+	h.response(w, r, Ok(songs), http.StatusOK)
+}
+
+// filtersError returns message of invalid query parameters of songs filters
+func filtersError(err error) string {
+	if errors.Is(err, models.ErrUnknownField) {
+		return err.Error()
+	}
+
+	return "limit, offset and id must be int"
+}
+
+// Export streams all songs into csv or jsonl file
+// @Summary Export songs
+// @Description Streams all songs that match filters without pagination, file is compatible with import if song and group are selected
+// @Tags songs
+// @Produce  plain
+// @Param format query string true "Format of file" Enums(csv, jsonl)
+// @Param id query int false "Song Id"
+// @Param song query string false "Song title"
+// @Param group query string false "Group name"
+// @Param date query string false "Song release date in format 02.01.2006"
+// @Param fields query string false "Comma separated fields of songs, all fields by default" example(id,song,group)
+// @Success 200 {string} string "CSV or JSONL file"
+// @Header 200 {string} X-Export-Status "Trailer with result of export: ok, or error if file is truncated"
+// @Failure 400 {object} Response "Invalid format or query parameters"
+// @Failure 500 {object} Response "Failed to export songs"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /songs/export [get]
+func (h *Handler) Export(w http.ResponseWriter, r *http.Request) {
+	var filters models.GetFilters
+
+	if err := filters.SetQueryData(r); err != nil {
+		h.response(w, r, Error(filtersError(err)), http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+
+	var contentType string
+
+	switch format {
+	case models.FormatCsv:
+		contentType = "text/csv; charset=utf-8"
+	case models.FormatJsonl:
+		contentType = "application/x-ndjson"
+	default:
+		h.response(w, r, Error("format must be csv or jsonl"), http.StatusBadRequest)
+		return
+	}
+
+	// Streaming of whole library could take longer than server timeout
+	clearDeadlines(w)
+
+	ctx := logger.NewCtxWithLog(r.Context(), h.log)
+
+	sw := &streamWriter{w: w, contentType: contentType, filename: "songs." + format}
+
+	if err := h.service.Export(ctx, sw, format, filters); err != nil {
+		logger.LogUse(ctx).Error(err.Error(), "input", slog.Any("filters", filters.LogValue()))
+
+		// Status can't be changed after the file is started, so truncation is reported in trailer
+		if sw.started {
+			sw.finish("error")
+		} else {
+			h.response(w, r, Error("Can't export songs"), http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	// Empty jsonl file is written without calling Write
+	if !sw.started {
+		sw.start()
+	}
+
+	sw.finish("ok")
 }
 
 // GetVerses returns paginated verses for a Song
 // @Summary Get song verses
-// @Description Returns paginated verses for the specified Song
+// @Description Returns paginated verses for the specified Song. Verses are returned as lyrics for Accept: text/plain and as page with song details for Accept: text/markdown
 // @Tags songs
-// @Produce  json
+// @Produce  json,plain,text/markdown
 // @Param id path int true "Song Id"
 // @Param limit query int false "Limit"
 // @Param offset query int false "Offset"
-// @Success 200 {array} string "Array of verses"
+// @Param type query string false "Section type" Enums(verse, chorus, pre-chorus, bridge, intro, outro, hook)
+// @Param timed query bool false "Return verses with time ranges from synchronized lyrics"
+// @Param lang query string false "Language of translation, original text is used if translation not exists"
+// @Param Accept-Language header string false "Preferred languages of translation, used if lang is not set"
+// @Param Accept header string false "Format of response, json by default" Enums(application/json, text/plain, text/markdown)
+// @Success 200 {array} string "Array of verses or models.Verse if timed"
 // @Failure 400 {object} Response "Invalid song Id or pagination parameters"
 // @Failure 404 {object} Response "Empty verses response"
 // @Failure 500 {object} Response "Failed to get Song's verses"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /songs/{id} [get]
 func (h *Handler) GetVerses(w http.ResponseWriter, r *http.Request) {
 	var filters models.GetVersesFilters
 
 	if err := filters.SetQueryId(r); err != nil {
-		h.response(w, Error("id must be int"), http.StatusBadRequest)
+		h.response(w, r, Error("id must be int"), http.StatusBadRequest)
 		return
 	}
 
 	if err := filters.SetQueryData(r); err != nil {
-		h.response(w, Error("limit and offset must be int"), http.StatusBadRequest)
+		h.response(w, r, Error("limit and offset must be int"), http.StatusBadRequest)
+		return
+	}
+
+	if filters.Type != "" && !models.IsSectionType(filters.Type) {
+		h.response(w, r, Error("Unknown section type"), http.StatusBadRequest)
 		return
 	}
 
+	if filters.Lang != "" {
+		lang, err := models.ParseLang(filters.Lang)
+		if err != nil {
+			h.response(w, r, Error("lang must be valid language tag"), http.StatusBadRequest)
+			return
+		}
+
+		filters.Lang = lang
+	}
+
+	w.Header().Add("Vary", "Accept-Language")
+
 	ctx := logger.NewCtxWithLog(r.Context(), h.log)
 
+	if filters.Timed {
+		verses, err := h.service.GetTimedVerses(ctx, filters)
+		if err != nil {
+			logger.LogUse(ctx).Error(err.Error(), "input", slog.Any("filters", filters.LogValue()))
+
+			h.response(w, r, Error("Can't get song"), http.StatusInternalServerError)
+			return
+		}
+
+		if verses == nil {
+			h.response(w, r, Error("Empty verses response"), http.StatusNotFound)
+			return
+		}
+
+		texts := make([]string, 0, len(verses))
+		for _, verse := range verses {
+			texts = append(texts, verse.Text)
+		}
+
+		h.response(w, r, Ok(verses).WithDocument(h.versesDocument(ctx, filters.Id, texts)), http.StatusOK)
+		return
+	}
+
 	verses, err := h.service.GetVerses(ctx, filters)
 	if err != nil {
-		h.log.Error(err.Error(), "input", slog.Any("filters", filters.AsLogValue()))
+		logger.LogUse(ctx).Error(err.Error(), "input", slog.Any("filters", filters.LogValue()))
 
-		h.response(w, Error("Can't get song"), http.StatusInternalServerError)
+		h.response(w, r, Error("Can't get song"), http.StatusInternalServerError)
 		return
 	}
 
 	if verses == nil {
-		h.response(w, Error("Empty verses response"), http.StatusNotFound)
+		h.response(w, r, Error("Empty verses response"), http.StatusNotFound)
+		return
+	}
+
+	h.response(w, r, Ok(verses).WithDocument(h.versesDocument(ctx, filters.Id, verses)), http.StatusOK)
+}
+
+// versesDocument creates document of song verses
+func (h *Handler) versesDocument(ctx context.Context, id int, verses []string) document {
+	return &versesDocument{ctx: ctx, service: h.service, id: id, verses: verses}
+}
+
+// GetSections returns paginated typed sections for a Song
+// @Summary Get song sections
+// @Description Returns paginated sections of the specified Song text with their types and labels
+// @Tags songs
+// @Produce  json
+// @Param id path int true "Song Id"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Param type query string false "Section type" Enums(verse, chorus, pre-chorus, bridge, intro, outro, hook)
+// @Param lang query string false "Language of translation, original text is used if translation not exists"
+// @Param Accept-Language header string false "Preferred languages of translation, used if lang is not set"
+// @Success 200 {array} models.Section "Array of sections"
+// @Failure 400 {object} Response "Invalid song Id, pagination parameters or section type"
+// @Failure 404 {object} Response "Song not found"
+// @Failure 500 {object} Response "Failed to get Song's sections"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /songs/{id}/sections [get]
+func (h *Handler) GetSections(w http.ResponseWriter, r *http.Request) {
+	var filters models.GetVersesFilters
+
+	if err := filters.SetQueryId(r); err != nil {
+		h.response(w, r, Error("id must be int"), http.StatusBadRequest)
+		return
+	}
+
+	if err := filters.SetQueryData(r); err != nil {
+		h.response(w, r, Error("limit and offset must be int"), http.StatusBadRequest)
+		return
+	}
+
+	if filters.Type != "" && !models.IsSectionType(filters.Type) {
+		h.response(w, r, Error("Unknown section type"), http.StatusBadRequest)
+		return
+	}
+
+	if filters.Lang != "" {
+		lang, err := models.ParseLang(filters.Lang)
+		if err != nil {
+			h.response(w, r, Error("lang must be valid language tag"), http.StatusBadRequest)
+			return
+		}
+
+		filters.Lang = lang
+	}
+
+	w.Header().Add("Vary", "Accept-Language")
+
+	ctx := logger.NewCtxWithLog(r.Context(), h.log)
+
+	sections, err := h.service.GetSections(ctx, filters)
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), "input", slog.Any("filters", filters.LogValue()))
+
+		h.response(w, r, Error("Can't get song sections"), http.StatusInternalServerError)
+		return
+	}
+
+	if sections == nil {
+		h.response(w, r, Error("Song not exists"), http.StatusNotFound)
+		return
+	}
+
+	h.response(w, r, Ok(sections), http.StatusOK)
+}
+
+// DeleteBatch deletes many songs at once
+// @Summary Delete many songs
+// @Description Deletes songs by ids and filters, at least one of them must be set. Dry run returns ids without deleting
+// @Tags songs
+// @Accept  json
+// @Produce  json
+// @Param filters body models.DeleteFilters true "Ids and filters of songs"
+// @Success 200 {object} models.DeleteResult "Ids of deleted songs"
+// @Failure 400 {object} Response "Invalid input"
+// @Failure 500 {object} Response "Failed to delete songs"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /songs:batch [delete]
+func (h *Handler) DeleteBatch(w http.ResponseWriter, r *http.Request) {
+	var filters models.DeleteFilters
+
+	if err := json.NewDecoder(r.Body).Decode(&filters); err != nil {
+		h.response(w, r, Error("Can't decode json body"), http.StatusBadRequest)
+		return
+	}
+
+	if filters.IsEmpty() {
+		h.response(w, r, Error("ids or filters must be set"), http.StatusBadRequest)
+		return
+	}
+
+	ctx := logger.NewCtxWithLog(r.Context(), h.log)
+
+	result, err := h.service.DeleteBatch(ctx, filters)
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), "input", filters.LogValue())
+
+		h.response(w, r, Error("Can't delete songs"), http.StatusInternalServerError)
+		return
+	}
+
+	h.response(w, r, Ok([]models.DeleteResult{result}), http.StatusOK)
+}
+
+// SetLyrics uploads synchronized lyrics for a Song
+// @Summary Upload synchronized lyrics
+// @Description Replaces synchronized lyrics of the specified Song with lines from LRC file, offset tag is applied to all lines
+// @Tags songs
+// @Accept  plain
+// @Produce  json
+// @Param id path int true "Song Id"
+// @Param lyrics body string true "LRC file"
+// @Success 200 {object} Response "Lyrics uploaded successfully"
+// @Failure 400 {object} Response "Invalid song Id or LRC syntax"
+// @Failure 404 {object} Response "Song not found"
+// @Failure 413 {object} Response "LRC file is too large"
+// @Failure 500 {object} Response "Failed to upload lyrics"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /songs/{id}/lyrics [put]
+func (h *Handler) SetLyrics(w http.ResponseWriter, r *http.Request) {
+	var song models.Song
+
+	if err := song.SetQueryId(r); err != nil {
+		h.response(w, r, Error("id must be int"), http.StatusBadRequest)
+		return
+	}
+
+	lyrics, err := lrc.Parse(http.MaxBytesReader(w, r.Body, maxLyricsSize))
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			h.response(w, r, Error(fmt.Sprintf("LRC file must not be larger than %d bytes", maxErr.Limit)), http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		h.response(w, r, Error("Invalid LRC file: "+err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	lines := make([]models.LyricLine, 0, len(lyrics.Lines))
+	for _, line := range lyrics.Lines {
+		lines = append(lines, models.LyricLine{Time: line.Time.Milliseconds(), Text: line.Text})
+	}
+
+	ctx := logger.NewCtxWithLog(r.Context(), h.log)
+
+	ok, err := h.service.SetLyrics(ctx, song.Id, lines)
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), "input", slog.Int("id", song.Id))
+
+		h.response(w, r, Error("Can't upload lyrics"), http.StatusInternalServerError)
+		return
+	}
+
+	if !ok {
+		h.response(w, r, Error("Song not exists"), http.StatusNotFound)
+		return
+	}
+
+	h.response(w, r, Ok(nil), http.StatusOK)
+}
+
+// GetLyrics returns synchronized lyrics of a Song
+// @Summary Get synchronized lyrics
+// @Description Returns synchronized lyrics of the specified Song as json or LRC file
+// @Tags songs
+// @Produce  json
+// @Produce  plain
+// @Param id path int true "Song Id"
+// @Param format query string false "Format of lyrics" Enums(json, lrc)
+// @Success 200 {object} models.Lyrics "Synchronized lyrics"
+// @Failure 400 {object} Response "Invalid song Id or format"
+// @Failure 404 {object} Response "Song or lyrics not found"
+// @Failure 500 {object} Response "Failed to get lyrics"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /songs/{id}/lyrics [get]
+func (h *Handler) GetLyrics(w http.ResponseWriter, r *http.Request) {
+	var song models.Song
+
+	if err := song.SetQueryId(r); err != nil {
+		h.response(w, r, Error("id must be int"), http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != formatJson && format != formatLrc {
+		h.response(w, r, Error("format must be json or lrc"), http.StatusBadRequest)
+		return
+	}
+
+	ctx := logger.NewCtxWithLog(r.Context(), h.log)
+
+	lyrics, ok, err := h.service.GetLyrics(ctx, song.Id)
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), "input", slog.Int("id", song.Id))
+
+		h.response(w, r, Error("Can't get lyrics"), http.StatusInternalServerError)
+		return
+	}
+
+	if !ok {
+		h.response(w, r, Error("Song not exists"), http.StatusNotFound)
+		return
+	}
+
+	if len(lyrics.Lines) == 0 {
+		h.response(w, r, Error("Song has no synchronized lyrics"), http.StatusNotFound)
+		return
+	}
+
+	if format != formatLrc {
+		h.response(w, r, Ok([]models.Lyrics{lyrics}), http.StatusOK)
+		return
+	}
+
+	file := lrc.Lyrics{
+		Tags: map[string]string{
+			lrc.TagTitle:  lyrics.Song,
+			lrc.TagArtist: lyrics.Group,
+		},
+	}
+
+	for _, line := range lyrics.Lines {
+		file.Lines = append(file.Lines, lrc.Line{Time: time.Duration(line.Time) * time.Millisecond, Text: line.Text})
+	}
+
+	var buf bytes.Buffer
+
+	if err := lrc.Write(&buf, file); err != nil {
+		logger.LogUse(ctx).Error(err.Error(), "input", slog.Int("id", song.Id))
+
+		h.response(w, r, Error("Can't get lyrics"), http.StatusInternalServerError)
 		return
 	}
 
-	h.response(w, Ok(verses), http.StatusOK)
+	h.text(w, r, "text/plain; charset=utf-8", buf.Bytes(), http.StatusOK)
 }
 
 // Delete deletes a song by Id
@@ -187,12 +679,14 @@ func (h *Handler) GetVerses(w http.ResponseWriter, r *http.Request) {
 // @Failure 400 {object} Response "Invalid song Id"
 // @Failure 404 {object} Response "Song not found"
 // @Failure 500 {object} Response "Failed to delete song"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /songs/{id} [delete]
 func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	var filters models.GetVersesFilters
 
 	if err := filters.SetQueryId(r); err != nil {
-		h.response(w, Error("id must be int"), http.StatusBadRequest)
+		h.response(w, r, Error("id must be int"), http.StatusBadRequest)
 		return
 	}
 
@@ -200,16 +694,16 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	ok, err := h.service.Delete(ctx, filters.Id)
 	if err != nil {
-		h.log.Error(err.Error(), "input", filters.AsLogValue())
+		logger.LogUse(ctx).Error(err.Error(), "input", filters.LogValue())
 
-		h.response(w, Error("Can't delete song"), http.StatusInternalServerError)
+		h.response(w, r, Error("Can't delete song"), http.StatusInternalServerError)
 		return
 	}
 
 	if !ok {
-		h.response(w, Error("Song not exists"), http.StatusNotFound)
+		h.response(w, r, Error("Song not exists"), http.StatusNotFound)
 		return
 	}
 
-	h.response(w, Ok(nil), http.StatusNoContent)
+	h.response(w, r, Ok(nil), http.StatusNoContent)
 }
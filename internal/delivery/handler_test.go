@@ -1,16 +1,24 @@
 package delivery
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/internal/service"
 	"github.com/s3nn1k/ef-mob-task/internal/service/mocks"
 	"github.com/s3nn1k/ef-mob-task/pkg/logger"
 	"github.com/s3nn1k/ef-mob-task/pkg/test"
+	testifymock "github.com/stretchr/testify/mock"
 )
 
 func TestCreate(t *testing.T) {
@@ -58,6 +66,412 @@ func TestCreate(t *testing.T) {
 	}
 }
 
+func TestLogRequestId(t *testing.T) {
+	mock := mocks.NewServiceIface(t)
+
+	var buf bytes.Buffer
+
+	log := logger.New(&buf, logger.FormatText, new(slog.LevelVar))
+
+	ctx := logger.NewCtxWithRequestId(context.Background(), "TestRequestId")
+
+	mock.On("Create", logger.NewCtxWithLog(ctx, log), "TestSong", "TestGroup").
+		Return(models.Song{}, errors.New("TestError"))
+
+	handler := NewHandler(log, mock)
+
+	req := httptest.NewRequest("POST", "/songs", strings.NewReader(`{"song":"TestSong", "group":"TestGroup"}`)).WithContext(ctx)
+	res := httptest.NewRecorder()
+
+	handler.Create(res, req)
+
+	if res.Code != http.StatusInternalServerError {
+		t.Fatalf("error: status missmatch. Want %v, but got %v", http.StatusInternalServerError, res.Code)
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if !strings.Contains(line, "requestId=TestRequestId") {
+			t.Fatalf("error: log line without request id: %s", line)
+		}
+	}
+
+	if !strings.Contains(buf.String(), "level=ERROR msg=TestError") {
+		t.Fatalf("error: error isn't logged: %s", buf.String())
+	}
+}
+
+func TestCreateBatch(t *testing.T) {
+	mock := mocks.NewServiceIface(t)
+
+	songs := []models.Song{
+		{Song: "TestSong", Group: "TestGroup"},
+		{Song: "Unknown", Group: "TestGroup"},
+	}
+
+	log := logger.NewTextLogger("")
+
+	mock.On("CreateBatch", logger.NewCtxWithLog(context.Background(), log), songs).
+		Return([]models.BatchResult{
+			{Index: 0, Song: &models.Song{Id: 1, Song: "TestSong", Group: "TestGroup"}},
+			{Index: 1, Error: "Can't get song details"},
+		}, nil)
+
+	testCases := []test.TestCase{
+		{
+			Name:       "success",
+			Body:       `[{"song":"TestSong","group":"TestGroup"},{"song":"Unknown","group":"TestGroup"}]`,
+			WantStatus: 200,
+			WantRes:    `{"status":"Ok","result":[{"index":0,"song":{"id":1,"song":"TestSong","group":"TestGroup","text":"","link":"","releaseDate":""}},{"index":1,"error":"Can't get song details"}]}`,
+		},
+		{
+			Name:       "empty",
+			Body:       `[]`,
+			WantStatus: 400,
+			WantRes:    `{"status":"Error","error":"count of songs must be from 1 to 1000"}`,
+		},
+	}
+
+	handler := NewHandler(log, mock)
+
+	router := http.NewServeMux()
+
+	router.HandleFunc("POST /songs:batch", http.HandlerFunc(handler.CreateBatch))
+
+	for _, testCase := range testCases {
+		testCase.Url = "/songs:batch"
+		testCase.Method = "POST"
+
+		test.TestEndpoint(t, router, testCase)
+	}
+}
+
+func TestDeleteBatch(t *testing.T) {
+	mock := mocks.NewServiceIface(t)
+
+	filters := models.DeleteFilters{
+		Ids:    []int{1, 2},
+		DryRun: true,
+	}
+
+	log := logger.NewTextLogger("")
+
+	mock.On("DeleteBatch", logger.NewCtxWithLog(context.Background(), log), filters).
+		Return(models.DeleteResult{Ids: []int{1}, DryRun: true}, nil)
+
+	testCases := []test.TestCase{
+		{
+			Name:       "success",
+			Body:       `{"ids":[1,2],"dryRun":true}`,
+			WantStatus: 200,
+			WantRes:    `{"status":"Ok","result":[{"ids":[1],"dryRun":true}]}`,
+		},
+		{
+			Name:       "empty filters",
+			Body:       `{"dryRun":true}`,
+			WantStatus: 400,
+			WantRes:    `{"status":"Error","error":"ids or filters must be set"}`,
+		},
+	}
+
+	handler := NewHandler(log, mock)
+
+	router := http.NewServeMux()
+
+	router.HandleFunc("DELETE /songs:batch", http.HandlerFunc(handler.DeleteBatch))
+
+	for _, testCase := range testCases {
+		testCase.Url = "/songs:batch"
+		testCase.Method = "DELETE"
+
+		test.TestEndpoint(t, router, testCase)
+	}
+}
+
+func TestImport(t *testing.T) {
+	mock := mocks.NewServiceIface(t)
+
+	log := logger.NewTextLogger("")
+
+	report := models.ImportReport{
+		Total:   2,
+		Created: 1,
+		Failed:  1,
+		Errors:  []models.RowError{{Row: 2, Error: "song and group must not be empty"}},
+	}
+
+	mock.On("Import", logger.NewCtxWithLog(context.Background(), log), testifymock.Anything, models.ImportOptions{Format: models.FormatCsv, Enrich: true}).
+		Return(report, nil)
+
+	mock.On("Import", logger.NewCtxWithLog(context.Background(), log), testifymock.Anything, models.ImportOptions{Format: models.FormatCsv}).
+		Return(models.ImportReport{}, fmt.Errorf("%w: csv header must contain song and group columns", service.ErrInvalidFile))
+
+	mock.On("Import", logger.NewCtxWithLog(context.Background(), log), testifymock.Anything, models.ImportOptions{Format: models.FormatJsonl}).
+		Return(models.ImportReport{}, errors.New("connection refused"))
+
+	testCases := []test.TestCase{
+		{
+			Name:       "success",
+			Url:        "/songs/import?format=csv&enrich=true",
+			Body:       "song,group\nTestSong,TestGroup\nTestSong,\n",
+			WantStatus: 200,
+			WantRes:    `{"status":"Ok","result":[{"total":2,"created":1,"failed":1,"errors":[{"row":2,"error":"song and group must not be empty"}]}]}`,
+		},
+		{
+			Name:       "unknown format",
+			Url:        "/songs/import?format=xml",
+			WantStatus: 400,
+			WantRes:    `{"status":"Error","error":"format must be csv or jsonl"}`,
+		},
+		{
+			Name:       "no format",
+			Url:        "/songs/import",
+			WantStatus: 400,
+			WantRes:    `{"status":"Error","error":"format must be csv or jsonl"}`,
+		},
+		{
+			Name:       "invalid file",
+			Url:        "/songs/import?format=csv",
+			Body:       "title,artist\n",
+			WantStatus: 400,
+			WantRes:    `{"status":"Error","error":"Can't import songs: invalid file: csv header must contain song and group columns"}`,
+		},
+		{
+			Name:       "storage error",
+			Url:        "/songs/import?format=jsonl",
+			WantStatus: 500,
+			WantRes:    `{"status":"Error","error":"Can't import songs"}`,
+		},
+	}
+
+	handler := NewHandler(log, mock)
+
+	router := http.NewServeMux()
+
+	router.HandleFunc("POST /songs/import", http.HandlerFunc(handler.Import))
+
+	for _, testCase := range testCases {
+		testCase.Method = "POST"
+
+		test.TestEndpoint(t, router, testCase)
+	}
+}
+
+func TestImportTimeout(t *testing.T) {
+	mock := mocks.NewServiceIface(t)
+
+	log := logger.NewTextLogger("")
+
+	var file []byte
+
+	mock.On("Import", testifymock.Anything, testifymock.Anything, models.ImportOptions{Format: models.FormatCsv}).
+		Run(func(args testifymock.Arguments) {
+			file, _ = io.ReadAll(args.Get(1).(io.Reader))
+		}).
+		Return(models.ImportReport{Total: 1, Created: 1}, nil)
+
+	handler := NewHandler(log, mock)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(handler.Import))
+	srv.Config.ReadTimeout = 50 * time.Millisecond
+	srv.Config.WriteTimeout = 50 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	body, pw := io.Pipe()
+
+	go func() {
+		pw.Write([]byte("song,group\n"))
+		time.Sleep(200 * time.Millisecond)
+		pw.Write([]byte("TestSong,TestGroup\n"))
+		pw.Close()
+	}()
+
+	res, err := http.Post(srv.URL+"/songs/import", "text/csv", body)
+	if err != nil {
+		t.Fatalf("error not expected while sending request: %s", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("error: status missmatch. Want %v, but got %v", http.StatusOK, res.StatusCode)
+	}
+
+	if want := "song,group\nTestSong,TestGroup\n"; string(file) != want {
+		t.Fatalf("error: want whole file %q, but got %q", want, file)
+	}
+}
+
+func TestExport(t *testing.T) {
+	mock := mocks.NewServiceIface(t)
+
+	log := logger.NewTextLogger("")
+
+	filters := models.GetFilters{
+		Limit: 10,
+		Group: "TestGroup",
+	}
+
+	mock.On("Export", logger.NewCtxWithLog(context.Background(), log), testifymock.Anything, models.FormatJsonl, filters).
+		Run(func(args testifymock.Arguments) {
+			fmt.Fprintln(args.Get(1).(io.Writer), `{"id":1,"song":"TestSong","group":"TestGroup"}`)
+		}).
+		Return(nil)
+
+	mock.On("Export", logger.NewCtxWithLog(context.Background(), log), testifymock.Anything, models.FormatCsv, filters).
+		Return(fmt.Errorf("storage is unavailable"))
+
+	fieldsFilters := filters
+	fieldsFilters.Fields = []string{models.FieldId, models.FieldSong}
+
+	mock.On("Export", logger.NewCtxWithLog(context.Background(), log), testifymock.Anything, models.FormatJsonl, fieldsFilters).
+		Run(func(args testifymock.Arguments) {
+			fmt.Fprintln(args.Get(1).(io.Writer), `{"id":1,"song":"TestSong"}`)
+		}).
+		Return(nil)
+
+	testCases := []test.TestCase{
+		{
+			Name:       "success",
+			Url:        "/songs/export?format=jsonl&group=TestGroup",
+			WantStatus: 200,
+			WantRes:    `{"id":1,"song":"TestSong","group":"TestGroup"}` + "\n",
+		},
+		{
+			Name:       "fields",
+			Url:        "/songs/export?format=jsonl&group=TestGroup&fields=song,id",
+			WantStatus: 200,
+			WantRes:    `{"id":1,"song":"TestSong"}` + "\n",
+		},
+		{
+			Name:       "unknown field",
+			Url:        "/songs/export?format=jsonl&fields=title",
+			WantStatus: 400,
+			WantRes:    `{"status":"Error","error":"unknown field \"title\", must be one of id, song, group, text, link, releaseDate"}`,
+		},
+		{
+			Name:       "fail",
+			Url:        "/songs/export?format=csv&group=TestGroup",
+			WantStatus: 500,
+			WantRes:    `{"status":"Error","error":"Can't export songs"}`,
+		},
+		{
+			Name:       "unknown format",
+			Url:        "/songs/export?format=xml",
+			WantStatus: 400,
+			WantRes:    `{"status":"Error","error":"format must be csv or jsonl"}`,
+		},
+	}
+
+	handler := NewHandler(log, mock)
+
+	router := http.NewServeMux()
+
+	router.HandleFunc("GET /songs/export", http.HandlerFunc(handler.Export))
+
+	for _, testCase := range testCases {
+		testCase.Method = "GET"
+
+		test.TestEndpoint(t, router, testCase)
+	}
+}
+
+func TestExportTrailer(t *testing.T) {
+	mock := mocks.NewServiceIface(t)
+
+	log := logger.NewTextLogger("")
+
+	mock.On("Export", testifymock.Anything, testifymock.Anything, models.FormatJsonl, testifymock.Anything).
+		Run(func(args testifymock.Arguments) {
+			fmt.Fprintln(args.Get(1).(io.Writer), `{"id":1,"song":"TestSong","group":"TestGroup"}`)
+		}).
+		Return(nil)
+
+	mock.On("Export", testifymock.Anything, testifymock.Anything, models.FormatCsv, testifymock.Anything).
+		Run(func(args testifymock.Arguments) {
+			fmt.Fprintln(args.Get(1).(io.Writer), "id,song,group")
+		}).
+		Return(fmt.Errorf("connection is lost"))
+
+	handler := NewHandler(log, mock)
+
+	srv := httptest.NewServer(http.HandlerFunc(handler.Export))
+	defer srv.Close()
+
+	testCases := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{name: "complete", format: models.FormatJsonl, want: "ok"},
+		{name: "truncated", format: models.FormatCsv, want: "error"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			res, err := http.Get(srv.URL + "/songs/export?format=" + testCase.format)
+			if err != nil {
+				t.Fatalf("error not expected while sending request: %s", err)
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != http.StatusOK {
+				t.Fatalf("error: status missmatch. Want %v, but got %v", http.StatusOK, res.StatusCode)
+			}
+
+			// Trailer is available only after body is read
+			if _, err := io.Copy(io.Discard, res.Body); err != nil {
+				t.Fatalf("error not expected while reading body: %s", err)
+			}
+
+			if got := res.Trailer.Get("X-Export-Status"); got != testCase.want {
+				t.Fatalf("error: export status missmatch. Want %s, but got %s", testCase.want, got)
+			}
+		})
+	}
+}
+
+func TestExportTimeout(t *testing.T) {
+	mock := mocks.NewServiceIface(t)
+
+	log := logger.NewTextLogger("")
+
+	mock.On("Export", testifymock.Anything, testifymock.Anything, models.FormatJsonl, testifymock.Anything).
+		Run(func(args testifymock.Arguments) {
+			w := args.Get(1).(io.Writer)
+
+			fmt.Fprintln(w, `{"id":1,"song":"TestSong","group":"TestGroup"}`)
+			time.Sleep(200 * time.Millisecond)
+			fmt.Fprintln(w, `{"id":2,"song":"OtherSong","group":"TestGroup"}`)
+		}).
+		Return(nil)
+
+	handler := NewHandler(log, mock)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(handler.Export))
+	srv.Config.WriteTimeout = 50 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL + "/songs/export?format=jsonl")
+	if err != nil {
+		t.Fatalf("error not expected while sending request: %s", err)
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("error not expected while reading body: %s", err)
+	}
+
+	if got := strings.Count(string(data), "\n"); got != 2 {
+		t.Fatalf("error: want 2 songs streamed past server timeout, but got %q", data)
+	}
+
+	if got := res.Trailer.Get("X-Export-Status"); got != "ok" {
+		t.Fatalf("error: want ok export status, but got %q", got)
+	}
+}
+
 func TestGetAll(t *testing.T) {
 	mock := mocks.NewServiceIface(t)
 
@@ -84,6 +498,10 @@ func TestGetAll(t *testing.T) {
 	mock.On("GetAll", logger.NewCtxWithLog(context.Background(), log), filters).
 		Return([]models.Song{song}, nil)
 
+	// Fields are deduplicated and sorted in order of response
+	mock.On("GetAll", logger.NewCtxWithLog(context.Background(), log), models.GetFilters{Limit: 10, Fields: []string{"id", "song", "group"}}).
+		Return([]models.Song{{Id: 1, Song: song.Song, Group: song.Group}}, nil)
+
 	testCases := []test.TestCase{
 		{
 			Name:       "success",
@@ -91,6 +509,18 @@ func TestGetAll(t *testing.T) {
 			WantStatus: 200,
 			WantRes:    `{"status":"Ok","result":[{"id":1,"song":"TestSong","group":"TestGroup","text":"TestText TestText","link":"TestLink","releaseDate":"TestDate"}]}`,
 		},
+		{
+			Name:       "fields",
+			Url:        "/songs?fields=group,%20song,id,song",
+			WantStatus: 200,
+			WantRes:    `{"status":"Ok","result":[{"id":1,"song":"TestSong","group":"TestGroup"}]}`,
+		},
+		{
+			Name:       "unknown field",
+			Url:        "/songs?fields=id,lyrics",
+			WantStatus: 400,
+			WantRes:    `{"status":"Error","error":"unknown field \"lyrics\", must be one of id, song, group, text, link, releaseDate"}`,
+		},
 		{
 			Name:       "invalid limit",
 			Url:        "/songs?limit=one",
@@ -126,6 +556,9 @@ func TestGetVerses(t *testing.T) {
 	mock.On("GetVerses", logger.NewCtxWithLog(context.Background(), log), filters).
 		Return([]string{"TestText", "TextText"}, nil)
 
+	mock.On("GetAll", logger.NewCtxWithLog(context.Background(), log), models.GetFilters{Id: 1, Limit: 1}).
+		Return([]models.Song{{Id: 1, Song: "Test_Song", Group: "TestGroup", Link: "TestLink", Date: "16.07.2006"}}, nil)
+
 	testCases := []test.TestCase{
 		{
 			Name:       "success",
@@ -133,6 +566,27 @@ func TestGetVerses(t *testing.T) {
 			WantStatus: 200,
 			WantRes:    `{"status":"Ok","result":["TestText","TextText"]}`,
 		},
+		{
+			Name:       "plain text",
+			Url:        "/songs/1?limit=1&offset=1",
+			Headers:    map[string]string{"Accept": "text/plain"},
+			WantStatus: 200,
+			WantRes:    "TestText\n\nTextText\n",
+		},
+		{
+			Name:       "markdown",
+			Url:        "/songs/1?limit=1&offset=1",
+			Headers:    map[string]string{"Accept": "text/markdown, application/json;q=0.5"},
+			WantStatus: 200,
+			WantRes:    "# Test\\_Song\n\n**Group:** TestGroup\n\n**Released:** 16.07.2006\n\n**Link:** <TestLink>\n\nTestText\n\nTextText\n",
+		},
+		{
+			Name:       "json by default",
+			Url:        "/songs/1?limit=1&offset=1",
+			Headers:    map[string]string{"Accept": "text/html, */*;q=0.8"},
+			WantStatus: 200,
+			WantRes:    `{"status":"Ok","result":["TestText","TextText"]}`,
+		},
 		{
 			Name:       "invalid id",
 			Url:        "/songs/kasjdf",
@@ -190,6 +644,150 @@ func TestFailGetVerses(t *testing.T) {
 	test.TestEndpoint(t, router, testCase)
 }
 
+func TestGetSections(t *testing.T) {
+	mock := mocks.NewServiceIface(t)
+
+	filters := models.GetVersesFilters{
+		Id:     1,
+		Limit:  10,
+		Offset: 0,
+		Type:   models.SectionChorus,
+	}
+
+	log := logger.NewTextLogger("")
+
+	mock.On("GetSections", logger.NewCtxWithLog(context.Background(), log), filters).
+		Return([]models.Section{{Type: models.SectionChorus, Label: "Chorus", Text: "TestText"}}, nil)
+
+	testCases := []test.TestCase{
+		{
+			Name:       "success",
+			Url:        "/songs/1/sections?type=Chorus",
+			WantStatus: 200,
+			WantRes:    `{"status":"Ok","result":[{"type":"chorus","label":"Chorus","text":"TestText"}]}`,
+		},
+		{
+			Name:       "invalid type",
+			Url:        "/songs/1/sections?type=solo",
+			WantStatus: 400,
+			WantRes:    `{"status":"Error","error":"Unknown section type"}`,
+		},
+	}
+
+	handler := NewHandler(log, mock)
+
+	router := http.NewServeMux()
+
+	router.HandleFunc("GET /songs/{id}/sections", http.HandlerFunc(handler.GetSections))
+
+	for _, testCase := range testCases {
+		testCase.Method = "GET"
+
+		test.TestEndpoint(t, router, testCase)
+	}
+}
+
+func TestSetLyrics(t *testing.T) {
+	mock := mocks.NewServiceIface(t)
+
+	lines := []models.LyricLine{
+		{Time: 1000, Text: "first"},
+		{Time: 2500, Text: "second"},
+	}
+
+	log := logger.NewTextLogger("")
+
+	mock.On("SetLyrics", logger.NewCtxWithLog(context.Background(), log), 1, lines).
+		Return(true, nil)
+
+	testCases := []test.TestCase{
+		{
+			Name:       "success",
+			Url:        "/songs/1/lyrics",
+			Body:       "[ti:TestSong]\n[00:01.00]first\n[00:02.50]second",
+			WantStatus: 200,
+			WantRes:    `{"status":"Ok"}`,
+		},
+		{
+			Name:       "invalid syntax",
+			Url:        "/songs/1/lyrics",
+			Body:       "first",
+			WantStatus: 400,
+			WantRes:    `{"status":"Error","error":"Invalid LRC file: line 1: invalid syntax, time or metadata tag expected"}`,
+		},
+		{
+			Name:       "too large",
+			Url:        "/songs/1/lyrics",
+			Body:       strings.Repeat("[00:01.00]first\n", maxLyricsSize/16+1),
+			WantStatus: 413,
+			WantRes:    `{"status":"Error","error":"LRC file must not be larger than 1048576 bytes"}`,
+		},
+	}
+
+	handler := NewHandler(log, mock)
+
+	router := http.NewServeMux()
+
+	router.HandleFunc("PUT /songs/{id}/lyrics", http.HandlerFunc(handler.SetLyrics))
+
+	for _, testCase := range testCases {
+		testCase.Method = "PUT"
+
+		test.TestEndpoint(t, router, testCase)
+	}
+}
+
+func TestGetLyrics(t *testing.T) {
+	mock := mocks.NewServiceIface(t)
+
+	lyrics := models.Lyrics{
+		Id:    1,
+		Song:  "TestSong",
+		Group: "TestGroup",
+		Lines: []models.LyricLine{
+			{Time: 1000, Text: "first"},
+		},
+	}
+
+	log := logger.NewTextLogger("")
+
+	mock.On("GetLyrics", logger.NewCtxWithLog(context.Background(), log), 1).
+		Return(lyrics, true, nil)
+
+	testCases := []test.TestCase{
+		{
+			Name:       "json",
+			Url:        "/songs/1/lyrics",
+			WantStatus: 200,
+			WantRes:    `{"status":"Ok","result":[{"id":1,"song":"TestSong","group":"TestGroup","lines":[{"timeMs":1000,"text":"first"}]}]}`,
+		},
+		{
+			Name:       "lrc",
+			Url:        "/songs/1/lyrics?format=lrc",
+			WantStatus: 200,
+			WantRes:    "[ar:TestGroup]\n[ti:TestSong]\n[00:01.00]first\n",
+		},
+		{
+			Name:       "invalid format",
+			Url:        "/songs/1/lyrics?format=srt",
+			WantStatus: 400,
+			WantRes:    `{"status":"Error","error":"format must be json or lrc"}`,
+		},
+	}
+
+	handler := NewHandler(log, mock)
+
+	router := http.NewServeMux()
+
+	router.HandleFunc("GET /songs/{id}/lyrics", http.HandlerFunc(handler.GetLyrics))
+
+	for _, testCase := range testCases {
+		testCase.Method = "GET"
+
+		test.TestEndpoint(t, router, testCase)
+	}
+}
+
 func TestUpdate(t *testing.T) {
 	mock := mocks.NewServiceIface(t)
 
@@ -341,3 +939,49 @@ func TestFailDelete(t *testing.T) {
 
 	test.TestEndpoint(t, router, testCase)
 }
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name    string
+		accept  string
+		wantRes string
+	}{
+		{
+			name:    "empty",
+			wantRes: mediaJson,
+		},
+		{
+			name:    "exact",
+			accept:  "text/plain",
+			wantRes: mediaText,
+		},
+		{
+			name:    "quality",
+			accept:  "application/json;q=0.4, text/markdown;q=0.9",
+			wantRes: mediaMarkdown,
+		},
+		{
+			name:    "type range",
+			accept:  "text/*",
+			wantRes: mediaText,
+		},
+		{
+			name:    "specific range wins",
+			accept:  "text/*;q=0.9, text/plain;q=0, */*;q=0.1",
+			wantRes: mediaMarkdown,
+		},
+		{
+			name:    "not acceptable",
+			accept:  "image/png",
+			wantRes: mediaJson,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if res := negotiate(test.accept, mediaJson, mediaText, mediaMarkdown); res != test.wantRes {
+				t.Fatalf("error: want %q, but got %q", test.wantRes, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+)
+
+// checkTimeout is the maximum duration of all readiness checks
+const checkTimeout = 2 * time.Second
+
+// type Check checks that dependency is available
+type Check func(ctx context.Context) error
+
+// type namedCheck represents check with name of dependency
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// type HealthHandler handles liveness and readiness probes
+type HealthHandler struct {
+	log      *slog.Logger
+	checks   []namedCheck
+	draining atomic.Bool
+}
+
+func NewHealthHandler(log *slog.Logger) *HealthHandler {
+	return &HealthHandler{
+		log: log,
+	}
+}
+
+// AddCheck adds check of dependency to readiness probe
+func (h *HealthHandler) AddCheck(name string, check Check) {
+	h.checks = append(h.checks, namedCheck{name: name, check: check})
+}
+
+// SetDraining makes readiness probe fail, so new requests aren't routed to app while it stops
+func (h *HealthHandler) SetDraining() {
+	h.draining.Store(true)
+}
+
+// Drain makes readiness probe fail and waits for delay, so probes notice it before listeners are closed
+// Returns error of context if it's done before delay
+func (h *HealthHandler) Drain(ctx context.Context, delay time.Duration) error {
+	h.SetDraining()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Live reports that process is alive
+// @Summary Liveness probe
+// @Description Returns Ok while process is able to handle requests
+// @Tags health
+// @Produce  json
+// @Success 200 {object} Response "Process is alive"
+// @Router /healthz [get]
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	h.response(w, Ok(nil), http.StatusOK)
+}
+
+// Ready reports that all dependencies are available
+// @Summary Readiness probe
+// @Description Checks all dependencies concurrently and returns result of every check. Fails while app is stopping
+// @Tags health
+// @Produce  json
+// @Success 200 {array} models.HealthCheck "All dependencies are available"
+// @Failure 503 {object} Response "Some of dependencies are not available or app is stopping"
+// @Router /readyz [get]
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	if h.draining.Load() {
+		h.response(w, Error("App is stopping"), http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+	defer cancel()
+
+	results := make([]models.HealthCheck, len(h.checks))
+	ready := true
+
+	var wg sync.WaitGroup
+
+	for i, check := range h.checks {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			results[i] = run(ctx, check)
+		}()
+	}
+
+	wg.Wait()
+
+	for _, res := range results {
+		if res.Status != models.HealthOk {
+			ready = false
+		}
+	}
+
+	if !ready {
+		logValues := make([]slog.Value, 0, len(results))
+		for _, res := range results {
+			logValues = append(logValues, res.LogValue())
+		}
+
+		h.log.Warn("Not ready", slog.Any("checks", logValues))
+
+		h.response(w, Response{Status: statusErr, Message: "Dependencies are not available", Result: results}, http.StatusServiceUnavailable)
+		return
+	}
+
+	h.response(w, Ok(results), http.StatusOK)
+}
+
+// run runs check and measures it's duration
+func run(ctx context.Context, check namedCheck) models.HealthCheck {
+	start := time.Now()
+
+	err := check.check(ctx)
+
+	res := models.HealthCheck{
+		Name:     check.name,
+		Status:   models.HealthOk,
+		Duration: time.Since(start).Milliseconds(),
+	}
+
+	if err != nil {
+		res.Status = models.HealthError
+		res.Error = err.Error()
+
+		if errors.Is(err, context.DeadlineExceeded) {
+			res.Error = "timeout"
+		}
+	}
+
+	return res
+}
+
+// response sends response without logging, probes are too frequent to log them
+func (h *HealthHandler) response(w http.ResponseWriter, r Response, status int) {
+	w.Header().Set("Content-Type", mediaJson)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(r); err != nil {
+		h.log.Error("Can't write probe response: " + err.Error())
+	}
+}
@@ -0,0 +1,160 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+)
+
+func TestReady(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	fail := func(ctx context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name       string
+		checks     map[string]Check
+		draining   bool
+		wantStatus int
+		wantFailed []string
+	}{
+		{
+			name:       "ready",
+			checks:     map[string]Check{"postgres": ok, "migrations": ok},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "dependency fails",
+			checks:     map[string]Check{"postgres": fail, "migrations": ok},
+			wantStatus: http.StatusServiceUnavailable,
+			wantFailed: []string{"postgres"},
+		},
+		{
+			name:       "draining",
+			checks:     map[string]Check{"postgres": ok},
+			draining:   true,
+			wantStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHealthHandler(logger.NewTextLogger(""))
+
+			for name, check := range tt.checks {
+				h.AddCheck(name, check)
+			}
+
+			if tt.draining {
+				h.SetDraining()
+			}
+
+			w := httptest.NewRecorder()
+			h.Ready(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("error: expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+
+			var res struct {
+				Result []struct {
+					Name   string `json:"name"`
+					Status string `json:"status"`
+					Error  string `json:"error"`
+				} `json:"result"`
+			}
+
+			if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+				t.Fatalf("error not expected while decoding: %s", err)
+			}
+
+			if tt.draining {
+				if len(res.Result) != 0 {
+					t.Fatal("error: checks must not run while draining")
+				}
+				return
+			}
+
+			if len(res.Result) != len(tt.checks) {
+				t.Fatalf("error: expected %d checks, got %d", len(tt.checks), len(res.Result))
+			}
+
+			var failed []string
+			for _, check := range res.Result {
+				if check.Status != statusOk {
+					failed = append(failed, check.Name)
+				}
+			}
+
+			if len(failed) != len(tt.wantFailed) || (len(failed) > 0 && failed[0] != tt.wantFailed[0]) {
+				t.Fatalf("error: expected failed checks %v, got %v", tt.wantFailed, failed)
+			}
+		})
+	}
+}
+
+func TestLive(t *testing.T) {
+	h := NewHealthHandler(logger.NewTextLogger(""))
+	h.SetDraining()
+
+	w := httptest.NewRecorder()
+	h.Live(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("error: liveness must not depend on draining, got status %d", w.Code)
+	}
+}
+
+func TestDrain(t *testing.T) {
+	h := NewHealthHandler(logger.NewTextLogger(""))
+
+	server := httptest.NewServer(http.HandlerFunc(h.Ready))
+	defer server.Close()
+
+	drained := make(chan error, 1)
+
+	go func() {
+		drained <- h.Drain(context.Background(), 200*time.Millisecond)
+	}()
+
+	// Server still accepts connections while drain delay lasts, so probes get 503 before it's closed
+	deadline := time.Now().Add(100 * time.Millisecond)
+
+	for {
+		res, err := http.Get(server.URL)
+		if err != nil {
+			t.Fatalf("error: server must accept connections while draining, but got %s", err)
+		}
+		res.Body.Close()
+
+		if res.StatusCode == http.StatusServiceUnavailable {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("error: want %d while draining, but got %d", http.StatusServiceUnavailable, res.StatusCode)
+		}
+	}
+
+	select {
+	case err := <-drained:
+		t.Fatalf("error: drain must wait for delay, but returned %v", err)
+	default:
+	}
+
+	if err := <-drained; err != nil {
+		t.Fatalf("error not expected after delay: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := h.Drain(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Fatalf("error: want context error, but got %v", err)
+	}
+}
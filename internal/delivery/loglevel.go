@@ -0,0 +1,76 @@
+package delivery
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+)
+
+// type LogLevelHandler changes logging level without restart
+type LogLevelHandler struct {
+	log   *slog.Logger
+	level *slog.LevelVar
+}
+
+func NewLogLevelHandler(l *slog.Logger, level *slog.LevelVar) *LogLevelHandler {
+	return &LogLevelHandler{
+		log:   l,
+		level: level,
+	}
+}
+
+// Get returns current logging level
+// @Summary Get log level
+// @Description Returns current logging level of app
+// @Tags admin
+// @Produce  json
+// @Success 200 {object} Response{result=models.LogLevel} "Current level"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /admin/log-level [get]
+func (h *LogLevelHandler) Get(w http.ResponseWriter, r *http.Request) {
+	h.response(w, r, Ok(models.LogLevel{Level: logger.LevelName(h.level.Level())}), http.StatusOK)
+}
+
+// Set changes logging level
+// @Summary Set log level
+// @Description Changes logging level of app without restart, level is reset to LOG_LEVEL after restart
+// @Tags admin
+// @Accept  json
+// @Produce  json
+// @Param level body models.LogLevel true "Level: debug, info, warning or error"
+// @Success 200 {object} Response{result=models.LogLevel} "Level changed"
+// @Failure 400 {object} Response "Invalid level"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /admin/log-level [put]
+func (h *LogLevelHandler) Set(w http.ResponseWriter, r *http.Request) {
+	var input models.LogLevel
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.response(w, r, Error("Can't decode json body"), http.StatusBadRequest)
+		return
+	}
+
+	level, err := logger.ParseLevel(input.Level)
+	if err != nil || input.Level == "" {
+		h.response(w, r, Error("level must be debug, info, warning or error"), http.StatusBadRequest)
+		return
+	}
+
+	prev := h.level.Level()
+
+	h.level.Set(level)
+
+	requestLog(h.log, r).Warn("Changed log level", slog.String("from", logger.LevelName(prev)), slog.String("to", input.Level))
+
+	h.response(w, r, Ok(input), http.StatusOK)
+}
+
+// response send's response and log's it
+func (h *LogLevelHandler) response(w http.ResponseWriter, req *http.Request, res Response, status int) {
+	respond(requestLog(h.log, req), w, res, status)
+}
@@ -0,0 +1,59 @@
+package delivery
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+)
+
+func TestSetLogLevel(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantLevel  slog.Level
+	}{
+		{
+			name:       "success",
+			body:       `{"level":"error"}`,
+			wantStatus: http.StatusOK,
+			wantLevel:  slog.LevelError,
+		},
+		{
+			name:       "unknown level",
+			body:       `{"level":"verbose"}`,
+			wantStatus: http.StatusBadRequest,
+			wantLevel:  slog.LevelInfo,
+		},
+		{
+			name:       "empty level",
+			body:       `{}`,
+			wantStatus: http.StatusBadRequest,
+			wantLevel:  slog.LevelInfo,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level := new(slog.LevelVar)
+			level.Set(slog.LevelInfo)
+
+			h := NewLogLevelHandler(logger.NewTextLogger(""), level)
+
+			w := httptest.NewRecorder()
+			h.Set(w, httptest.NewRequest(http.MethodPut, "/admin/log-level", strings.NewReader(tt.body)))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("error: expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+
+			if level.Level() != tt.wantLevel {
+				t.Fatalf("error: expected level %s, got %s", tt.wantLevel, level.Level())
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+)
+
+// apiKeyPrefix is the prefix of API keys, used to tell them apart from tokens in Authorization header
+const apiKeyPrefix = "sk_"
+
+// principalCtx is the context key of authenticated principal
+type principalCtx struct{}
+
+// type Authenticator checks API keys
+type Authenticator interface {
+	Authenticate(ctx context.Context, secret string) (models.APIKey, bool, error)
+}
+
+// type TokenVerifier checks bearer tokens
+type TokenVerifier interface {
+	Verify(token string) (models.Principal, error)
+}
+
+// type Auth authenticates requests by API keys and bearer tokens
+// Any of them could be nil to disable that kind of credentials
+type Auth struct {
+	log    *slog.Logger
+	keys   Authenticator
+	tokens TokenVerifier
+}
+
+func NewAuth(log *slog.Logger, keys Authenticator, tokens TokenVerifier) *Auth {
+	return &Auth{
+		log:    log,
+		keys:   keys,
+		tokens: tokens,
+	}
+}
+
+// type authError represents failed authentication or authorization
+// Status is the http status of error, gRPC interceptors map it into code
+type authError struct {
+	Status  int
+	Message string
+}
+
+// Require allows request only for principal with the given role
+// API key is taken from X-API-Key header, token or API key from Authorization header with Bearer scheme.
+// Principal is set into request context
+func (a *Auth) Require(role string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secret, token := credentials(r.Header.Get("X-API-Key"), r.Header.Get("Authorization"))
+
+		principal, err := a.check(r.Context(), secret, token, role, r.URL.Path)
+		if err != nil {
+			if err.Status == http.StatusUnauthorized {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="songs"`)
+			}
+
+			errorResponse(w, err.Message, err.Status)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(NewCtxWithPrincipal(r.Context(), principal)))
+	})
+}
+
+// check authenticates credentials and checks that principal has the given role
+// Path is the route or method that is called, it's used only for logging
+func (a *Auth) check(ctx context.Context, secret string, token string, role string, path string) (models.Principal, *authError) {
+	principal, err := a.authenticate(ctx, secret, token, path)
+	if err != nil {
+		return models.Principal{}, err
+	}
+
+	if !principal.HasRole(role) {
+		logger.LogWith(ctx, a.log).Warn("Forbidden", "principal", principal.LogValue(), slog.String("required", role), slog.String("path", path))
+
+		return models.Principal{}, &authError{Status: http.StatusForbidden, Message: "Role " + role + " is required"}
+	}
+
+	return principal, nil
+}
+
+// authenticate returns principal for API key or bearer token
+func (a *Auth) authenticate(ctx context.Context, secret string, token string, path string) (models.Principal, *authError) {
+	if secret != "" && a.keys != nil {
+		key, ok, err := a.keys.Authenticate(ctx, secret)
+		if err != nil {
+			logger.LogWith(ctx, a.log).Error(err.Error(), slog.String("path", path))
+
+			return models.Principal{}, &authError{Status: http.StatusInternalServerError, Message: "Can't check API key"}
+		}
+
+		if !ok {
+			return models.Principal{}, &authError{Status: http.StatusUnauthorized, Message: "Invalid API key"}
+		}
+
+		return models.Principal{
+			Kind:    models.PrincipalKey,
+			Subject: strconv.Itoa(key.Id),
+			Roles:   []string{key.Scope},
+		}, nil
+	}
+
+	if token != "" && a.tokens != nil {
+		principal, err := a.tokens.Verify(token)
+		if err != nil {
+			logger.LogWith(ctx, a.log).Debug("Invalid token", slog.String("error", err.Error()), slog.String("path", path))
+
+			return models.Principal{}, &authError{Status: http.StatusUnauthorized, Message: "Invalid token"}
+		}
+
+		return principal, nil
+	}
+
+	return models.Principal{}, &authError{Status: http.StatusUnauthorized, Message: "Credentials are required"}
+}
+
+// NewCtxWithPrincipal sets principal into context
+func NewCtxWithPrincipal(ctx context.Context, principal models.Principal) context.Context {
+	return context.WithValue(ctx, principalCtx{}, principal)
+}
+
+// PrincipalFromContext returns principal that authenticated request
+func PrincipalFromContext(ctx context.Context) (models.Principal, bool) {
+	principal, ok := ctx.Value(principalCtx{}).(models.Principal)
+	return principal, ok
+}
+
+// credentials returns API key or bearer token from values of X-API-Key and Authorization headers
+func credentials(apiKey string, authorization string) (secret string, token string) {
+	if key := strings.TrimSpace(apiKey); key != "" {
+		return key, ""
+	}
+
+	scheme, val, ok := strings.Cut(authorization, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", ""
+	}
+
+	val = strings.TrimSpace(val)
+
+	if strings.HasPrefix(val, apiKeyPrefix) {
+		return val, ""
+	}
+
+	return "", val
+}
+
+// errorResponse sends error in the same json format as handlers
+func errorResponse(w http.ResponseWriter, msg string, status int) {
+	data, _ := json.Marshal(struct {
+		Status  string `json:"status"`
+		Message string `json:"error"`
+	}{"Error", msg})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+	"github.com/s3nn1k/ef-mob-task/pkg/test"
+)
+
+// type keys authenticates keys from map
+type keys map[string]models.APIKey
+
+func (k keys) Authenticate(ctx context.Context, secret string) (models.APIKey, bool, error) {
+	key, ok := k[secret]
+	return key, ok, nil
+}
+
+// type tokens verifies tokens from map
+type tokens map[string]models.Principal
+
+func (t tokens) Verify(token string) (models.Principal, error) {
+	principal, ok := t[token]
+	if !ok {
+		return models.Principal{}, errors.New("invalid token")
+	}
+
+	return principal, nil
+}
+
+func TestRequire(t *testing.T) {
+	auth := keys{
+		"sk_reader": {Id: 1, Name: "reader", Scope: models.ScopeRead},
+		"sk_admin":  {Id: 2, Name: "admin", Scope: models.ScopeAdmin},
+	}
+
+	verifier := tokens{
+		"reader.jwt": {Kind: models.PrincipalToken, Subject: "user1", Roles: []string{models.ScopeRead}},
+		"writer.jwt": {Kind: models.PrincipalToken, Subject: "user2", Roles: []string{"unknown", models.ScopeWrite}},
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := PrincipalFromContext(r.Context()); !ok {
+			t.Fatal("error: principal must be set into context")
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	router := http.NewServeMux()
+
+	router.Handle("DELETE /songs/{id}", NewAuth(logger.NewTextLogger(""), auth, verifier).Require(models.ScopeWrite, next))
+
+	testCases := []test.TestCase{
+		{
+			Name:       "no credentials",
+			WantStatus: 401,
+			WantRes:    `{"status":"Error","error":"Credentials are required"}`,
+		},
+		{
+			Name:       "invalid key",
+			Headers:    map[string]string{"X-API-Key": "sk_unknown"},
+			WantStatus: 401,
+			WantRes:    `{"status":"Error","error":"Invalid API key"}`,
+		},
+		{
+			Name:       "low scope",
+			Headers:    map[string]string{"X-API-Key": "sk_reader"},
+			WantStatus: 403,
+			WantRes:    `{"status":"Error","error":"Role write is required"}`,
+		},
+		{
+			Name:       "bearer key",
+			Headers:    map[string]string{"Authorization": "Bearer sk_admin"},
+			WantStatus: 204,
+		},
+		{
+			Name:       "invalid token",
+			Headers:    map[string]string{"Authorization": "Bearer forged.jwt"},
+			WantStatus: 401,
+			WantRes:    `{"status":"Error","error":"Invalid token"}`,
+		},
+		{
+			Name:       "token without role",
+			Headers:    map[string]string{"Authorization": "Bearer reader.jwt"},
+			WantStatus: 403,
+			WantRes:    `{"status":"Error","error":"Role write is required"}`,
+		},
+		{
+			Name:       "token",
+			Headers:    map[string]string{"Authorization": "bearer writer.jwt"},
+			WantStatus: 204,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase.Url = "/songs/1"
+		testCase.Method = "DELETE"
+
+		test.TestEndpoint(t, router, testCase)
+	}
+}
@@ -0,0 +1,288 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// type serverStream replaces context of gRPC stream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// UnaryRequestId sets id of request into context and response headers, the same as WithRequestId
+func UnaryRequestId(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	return handler(grpcRequestId(ctx), req)
+}
+
+// StreamRequestId sets id of request into context and response headers, the same as WithRequestId
+func StreamRequestId(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &serverStream{ServerStream: ss, ctx: grpcRequestId(ss.Context())})
+}
+
+// grpcRequestId takes id of request from metadata or generates new one
+func grpcRequestId(ctx context.Context) context.Context {
+	id := firstMetadata(ctx, HeaderRequestId)
+	if !validRequestId(id) {
+		id = newRequestId()
+	}
+
+	grpc.SetHeader(ctx, metadata.Pairs(HeaderRequestId, id))
+
+	return logger.NewCtxWithRequestId(ctx, id)
+}
+
+// UnaryLogging writes access log of every unary call, the same as WithLogging
+func UnaryLogging(log *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+
+		res, err := handler(ctx, req)
+
+		logCall(ctx, log, info.FullMethod, err, start)
+
+		return res, err
+	}
+}
+
+// StreamLogging writes access log of every streaming call, the same as WithLogging
+func StreamLogging(log *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+
+		err := handler(srv, ss)
+
+		logCall(ss.Context(), log, info.FullMethod, err, start)
+
+		return err
+	}
+}
+
+// logCall writes access log of gRPC call
+func logCall(ctx context.Context, log *slog.Logger, method string, err error, start time.Time) {
+	entry := log.With(
+		slog.String("method", method),
+		slog.String("userAgent", firstMetadata(ctx, "user-agent")),
+	)
+
+	if p, ok := peer.FromContext(ctx); ok {
+		entry = entry.With(slog.String("addr", p.Addr.String()))
+	}
+
+	if id := logger.RequestId(ctx); id != "" {
+		entry = entry.With(slog.String("requestId", id))
+	}
+
+	entry.Info(
+		"Request",
+		slog.String("code", status.Code(err).String()),
+		slog.Duration("duration", time.Since(start)),
+	)
+}
+
+// Unary allows call only for principal with the role of method, the same as Require
+// Credentials are taken from x-api-key or authorization metadata. Methods without role require admin scope
+func (a *Auth) Unary(roles map[string]string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := a.grpcCheck(ctx, roles, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// Stream allows call only for principal with the role of method, the same as Require
+// Credentials are taken from x-api-key or authorization metadata. Methods without role require admin scope
+func (a *Auth) Stream(roles map[string]string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.grpcCheck(ss.Context(), roles, info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// grpcCheck authenticates call and sets principal into context
+func (a *Auth) grpcCheck(ctx context.Context, roles map[string]string, method string) (context.Context, error) {
+	role, ok := roles[method]
+	if !ok {
+		role = models.ScopeAdmin
+	}
+
+	secret, token := credentials(firstMetadata(ctx, "x-api-key"), firstMetadata(ctx, "authorization"))
+
+	principal, err := a.check(ctx, secret, token, role, method)
+	if err != nil {
+		return nil, status.Error(GRPCCode(err.Status), err.Message)
+	}
+
+	return NewCtxWithPrincipal(ctx, principal), nil
+}
+
+// Unary limits calls of every client with limits of REST routes that methods are mapped to, the same as Limit
+// Method and it's route share buckets, methods without route have the default limit
+func (l *RateLimiter) Unary(routes map[string]string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := l.grpcTake(ctx, routes, info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// Stream limits calls of every client with limits of REST routes that methods are mapped to, the same as Limit
+// Method and it's route share buckets, methods without route have the default limit
+func (l *RateLimiter) Stream(routes map[string]string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := l.grpcTake(ss.Context(), routes, info.FullMethod); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+// UnaryAddr limits calls by remote address, the same as LimitAddr, it's used in front of auth
+func (l *RateLimiter) UnaryAddr(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := l.grpcTakeAddr(ctx); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// StreamAddr limits calls by remote address, the same as LimitAddr, it's used in front of auth
+func (l *RateLimiter) StreamAddr(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := l.grpcTakeAddr(ss.Context()); err != nil {
+		return err
+	}
+
+	return handler(srv, ss)
+}
+
+// grpcTakeAddr takes token of call from bucket of remote address, only retry-after header is sent if call isn't allowed
+func (l *RateLimiter) grpcTakeAddr(ctx context.Context) error {
+	if !limited(l.cfg.Addr) {
+		return nil
+	}
+
+	allowed, _, _, retry := l.take(remote(grpcAddr(ctx)), l.cfg.Addr)
+	if !allowed {
+		grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(seconds(retry))))
+
+		return status.Error(codes.ResourceExhausted, "Too many requests")
+	}
+
+	return nil
+}
+
+// grpcTake takes token of call from bucket of client, ratelimit-* headers are sent with response
+func (l *RateLimiter) grpcTake(ctx context.Context, routes map[string]string, method string) error {
+	pattern, ok := routes[method]
+	if !ok {
+		pattern = method
+	}
+
+	limit, ok := l.routeLimit(pattern)
+	if !ok {
+		return nil
+	}
+
+	allowed, remaining, reset, retry := l.take(pattern+" "+grpcClient(ctx), limit)
+
+	md := metadata.Pairs(
+		"ratelimit-limit", strconv.Itoa(limit.Count),
+		"ratelimit-remaining", strconv.Itoa(remaining),
+		"ratelimit-reset", strconv.Itoa(seconds(reset)),
+	)
+
+	if !allowed {
+		md.Set("retry-after", strconv.Itoa(seconds(retry)))
+	}
+
+	grpc.SetHeader(ctx, md)
+
+	if !allowed {
+		return status.Error(codes.ResourceExhausted, "Too many requests")
+	}
+
+	return nil
+}
+
+// grpcClient returns identifier of call's client, the same as of HTTP request
+func grpcClient(ctx context.Context) string {
+	return clientKey(ctx, grpcAddr(ctx))
+}
+
+// grpcAddr returns address of call's peer
+func grpcAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok {
+		return p.Addr.String()
+	}
+
+	return ""
+}
+
+// GRPCCode returns gRPC code that matches http status of response
+func GRPCCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+
+	if httpStatus < http.StatusBadRequest {
+		return codes.OK
+	}
+
+	if httpStatus < http.StatusInternalServerError {
+		return codes.FailedPrecondition
+	}
+
+	return codes.Internal
+}
+
+// firstMetadata returns the first value of incoming metadata with the given key
+func firstMetadata(ctx context.Context, key string) string {
+	vals := metadata.ValueFromIncomingContext(ctx, strings.ToLower(key))
+	if len(vals) == 0 {
+		return ""
+	}
+
+	return vals[0]
+}
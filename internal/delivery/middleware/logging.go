@@ -4,26 +4,38 @@ import (
 	"log/slog"
 	"net/http"
 	"time"
+
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
 )
 
-// Withlogging logs every incoming request
+// WithLogging writes access log of every incoming request
+// Request id is logged if it was set into context by WithRequestId
 func WithLogging(log *slog.Logger, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		entry := log.With(
-			slog.String("method", r.Method),
-			slog.String("path", r.URL.Path),
-			slog.String("addr", r.RemoteAddr),
-		)
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
 
 		start := time.Now()
 
 		defer func() {
+			entry := log.With(
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.String("addr", r.RemoteAddr),
+				slog.String("userAgent", r.UserAgent()),
+			)
+
+			if id := logger.RequestId(r.Context()); id != "" {
+				entry = entry.With(slog.String("requestId", id))
+			}
+
 			entry.Info(
-				"Requset",
+				"Request",
+				slog.Int("status", sw.status),
+				slog.Int("bytes", sw.size),
 				slog.Duration("duration", time.Since(start)),
 			)
 		}()
 
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(sw, r)
 	})
 }
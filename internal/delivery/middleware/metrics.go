@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+)
+
+// type RequestRecorder records handled requests
+type RequestRecorder interface {
+	ObserveRequest(route string, status int, duration time.Duration)
+}
+
+// WithMetrics records status and duration of every request to route with the given pattern
+func WithMetrics(rec RequestRecorder, pattern string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+
+		start := time.Now()
+
+		next.ServeHTTP(sw, r)
+
+		rec.ObserveRequest(pattern, sw.status, time.Since(start))
+	})
+}
+
+// type statusWriter remembers status and size of response
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	size        int
+	wroteHeader bool
+}
+
+func (s *statusWriter) WriteHeader(status int) {
+	if !s.wroteHeader {
+		s.status = status
+		s.wroteHeader = true
+	}
+
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func (s *statusWriter) Write(p []byte) (int, error) {
+	s.wroteHeader = true
+
+	n, err := s.ResponseWriter.Write(p)
+	s.size += n
+
+	return n, err
+}
+
+// Unwrap allows http.ResponseController to reach the original writer
+func (s *statusWriter) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// type recorder remembers the last observed request
+type recorder struct {
+	route  string
+	status int
+}
+
+func (r *recorder) ObserveRequest(route string, status int, duration time.Duration) {
+	r.route, r.status = route, status
+}
+
+func TestWithMetrics(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		wantStatus int
+	}{
+		{
+			name: "implicit status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("ok"))
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "explicit status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := &recorder{}
+
+			handler := WithMetrics(rec, "GET /songs/{id}", test.handler)
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/songs/1", nil))
+
+			if rec.route != "GET /songs/{id}" || rec.status != test.wantStatus {
+				t.Fatalf("error: want route pattern with %v status, but got %s with %v", test.wantStatus, rec.route, rec.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/s3nn1k/ef-mob-task/internal/config"
+)
+
+// sweepInterval is the period of removing buckets of inactive clients
+const sweepInterval = time.Minute
+
+// type bucket represents tokens of one client for one route
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// type RateLimiter limits requests of every client with token bucket
+// Clients are identified by authenticated principal or remote address
+type RateLimiter struct {
+	cfg config.RateLimit
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+
+	// now is replaced in tests
+	now func() time.Time
+}
+
+func NewRateLimiter(cfg config.RateLimit) *RateLimiter {
+	return &RateLimiter{
+		cfg:       cfg,
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Limit limits requests of every client to route with limit of it's pattern or the default one
+// RateLimit-* headers are set for every request, Retry-After is set with 429 response
+func (l *RateLimiter) Limit(pattern string, next http.Handler) http.Handler {
+	return l.limit(pattern, client, next)
+}
+
+// LimitAddr limits requests to all routes by remote address with address limit
+// It's used in front of auth, so requests with missing or invalid credentials are limited too.
+// Many clients could share one address, so only Retry-After is set and RateLimit-* headers are left to Limit
+func (l *RateLimiter) LimitAddr(next http.Handler) http.Handler {
+	if !limited(l.cfg.Addr) {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowed, _, _, retry := l.take(remote(r.RemoteAddr), l.cfg.Addr)
+		if !allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(seconds(retry)))
+
+			errorResponse(w, "Too many requests", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// limit limits requests to route, buckets are identified by key of request
+func (l *RateLimiter) limit(pattern string, key func(r *http.Request) string, next http.Handler) http.Handler {
+	limit, ok := l.routeLimit(pattern)
+	if !ok {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowed, remaining, reset, retry := l.take(pattern+" "+key(r), limit)
+
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(limit.Count))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(remaining))
+		w.Header().Set("RateLimit-Reset", strconv.Itoa(seconds(reset)))
+
+		if !allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(seconds(retry)))
+
+			errorResponse(w, "Too many requests", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// routeLimit returns limit of route pattern or the default one, false if route isn't limited
+func (l *RateLimiter) routeLimit(pattern string) (config.Limit, bool) {
+	limit, ok := l.cfg.Routes[pattern]
+	if !ok {
+		limit = l.cfg.Default
+	}
+
+	return limit, limited(limit)
+}
+
+// limited checks that limit is set
+func limited(limit config.Limit) bool {
+	return limit.Count > 0 && limit.Period > 0
+}
+
+// take takes token from bucket of key
+// Returns remaining tokens, time until bucket is full and time until the next token if request isn't allowed
+func (l *RateLimiter) take(key string, limit config.Limit) (bool, int, time.Duration, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	rate := float64(limit.Count) / limit.Period.Seconds()
+
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Count), updated: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(float64(limit.Count), b.tokens+now.Sub(b.updated).Seconds()*rate)
+	b.updated = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	reset := duration((float64(limit.Count) - b.tokens) / rate)
+
+	var retry time.Duration
+	if !allowed {
+		retry = duration((1 - b.tokens) / rate)
+	}
+
+	return allowed, int(b.tokens), reset, retry
+}
+
+// sweep removes buckets that have been refilled, they are equal to new ones
+// Buckets are refilled after the longest period at most
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+
+	l.lastSweep = now
+
+	longest := max(l.cfg.Default.Period, l.cfg.Addr.Period)
+	for _, limit := range l.cfg.Routes {
+		longest = max(longest, limit.Period)
+	}
+
+	for key, b := range l.buckets {
+		if now.Sub(b.updated) > longest {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// client returns identifier of request's client
+func client(r *http.Request) string {
+	return clientKey(r.Context(), r.RemoteAddr)
+}
+
+// clientKey returns identifier of client, that is the same for HTTP requests and gRPC calls
+// Principal is used if client is authenticated, remote address otherwise
+func clientKey(ctx context.Context, addr string) string {
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		return principal.Kind + ":" + principal.Subject
+	}
+
+	return "addr:" + host(addr)
+}
+
+// remote returns key of address bucket, that is shared by all routes and gRPC methods
+func remote(addr string) string {
+	return "remote:" + host(addr)
+}
+
+// host returns host of remote address without port
+func host(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	return host
+}
+
+func duration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// seconds returns duration in whole seconds rounded up
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/s3nn1k/ef-mob-task/internal/config"
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter(config.RateLimit{
+		Default: config.Limit{Count: 100, Period: time.Minute},
+		Routes: map[string]config.Limit{
+			"POST /songs": {Count: 2, Period: time.Minute},
+		},
+	})
+
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	router := http.NewServeMux()
+
+	router.Handle("POST /songs", limiter.Limit("POST /songs", next))
+	router.Handle("GET /songs", limiter.Limit("GET /songs", next))
+
+	do := func(method string, addr string, principal *models.Principal) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/songs", nil)
+		req.RemoteAddr = addr
+
+		if principal != nil {
+			req = req.WithContext(NewCtxWithPrincipal(req.Context(), *principal))
+		}
+
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		return res
+	}
+
+	for i, wantRemaining := range []string{"1", "0"} {
+		res := do("POST", "10.0.0.1:1000", nil)
+		if res.Code != http.StatusOK || res.Header().Get("RateLimit-Remaining") != wantRemaining {
+			t.Fatalf("error: request %d must be allowed with %s remaining, but got %v and %s", i, wantRemaining, res.Code, res.Header().Get("RateLimit-Remaining"))
+		}
+	}
+
+	res := do("POST", "10.0.0.1:2000", nil)
+	if res.Code != http.StatusTooManyRequests {
+		t.Fatalf("error: want %v, but got %v", http.StatusTooManyRequests, res.Code)
+	}
+
+	if res.Header().Get("Retry-After") != "30" || res.Header().Get("RateLimit-Reset") != "60" {
+		t.Fatalf("error: want Retry-After 30 and RateLimit-Reset 60, but got %s and %s", res.Header().Get("Retry-After"), res.Header().Get("RateLimit-Reset"))
+	}
+
+	if res.Body.String() != `{"status":"Error","error":"Too many requests"}` {
+		t.Fatalf("error: body missmatch, got %s", res.Body.String())
+	}
+
+	if res := do("GET", "10.0.0.1:1000", nil); res.Code != http.StatusOK || res.Header().Get("RateLimit-Limit") != "100" {
+		t.Fatalf("error: other route must have default limit, but got %v and %s", res.Code, res.Header().Get("RateLimit-Limit"))
+	}
+
+	if res := do("POST", "10.0.0.1:1000", &models.Principal{Kind: models.PrincipalKey, Subject: "1"}); res.Code != http.StatusOK {
+		t.Fatalf("error: authenticated client must have own bucket, but got %v", res.Code)
+	}
+
+	now = now.Add(30 * time.Second)
+
+	if res := do("POST", "10.0.0.1:1000", nil); res.Code != http.StatusOK {
+		t.Fatalf("error: token must be refilled, but got %v", res.Code)
+	}
+}
+
+func TestRateLimiterAddr(t *testing.T) {
+	limiter := NewRateLimiter(config.RateLimit{
+		Default: config.Limit{Count: 1, Period: time.Minute},
+		Addr:    config.Limit{Count: 2, Period: time.Minute},
+	})
+
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	// Requests are rejected by auth, but still take tokens of remote address
+	unauthorized := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	handler := limiter.LimitAddr(unauthorized)
+
+	do := func(addr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/songs", nil)
+		req.RemoteAddr = addr
+
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+
+		return res
+	}
+
+	// Address limit is used instead of the default one
+	for i, want := range []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests} {
+		res := do("10.0.0.1:1000")
+		if res.Code != want {
+			t.Fatalf("error: request %d must get %v, but got %v", i, want, res.Code)
+		}
+
+		if res.Header().Get("RateLimit-Limit") != "" {
+			t.Fatalf("error: RateLimit-* headers must be set only by limit of principal, but got %v", res.Header())
+		}
+	}
+
+	if res := do("10.0.0.1:1000"); res.Header().Get("Retry-After") != "30" {
+		t.Fatalf("error: want Retry-After 30, but got %s", res.Header().Get("Retry-After"))
+	}
+
+	if res := do("10.0.0.2:1000"); res.Code != http.StatusUnauthorized {
+		t.Fatalf("error: other address must have own bucket, but got %v", res.Code)
+	}
+
+	// Clients behind one address have own limits of routes
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	handler = limiter.LimitAddr(limiter.Limit("GET /songs", ok))
+
+	for _, subject := range []string{"1", "2"} {
+		req := httptest.NewRequest("GET", "/songs", nil)
+		req.RemoteAddr = "10.0.0.3:1000"
+		req = req.WithContext(NewCtxWithPrincipal(req.Context(), models.Principal{Kind: models.PrincipalKey, Subject: subject}))
+
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+
+		if res.Code != http.StatusOK || res.Header().Get("RateLimit-Remaining") != "0" {
+			t.Fatalf("error: key %s must be limited by own bucket, but got %v and %s", subject, res.Code, res.Header().Get("RateLimit-Remaining"))
+		}
+	}
+
+	// Address isn't limited without address limit
+	handler = NewRateLimiter(config.RateLimit{Default: config.Limit{Count: 1, Period: time.Minute}}).LimitAddr(unauthorized)
+
+	for i := range 3 {
+		if res := do("10.0.0.1:1000"); res.Code != http.StatusUnauthorized {
+			t.Fatalf("error: request %d must not be limited, but got %v", i, res.Code)
+		}
+	}
+}
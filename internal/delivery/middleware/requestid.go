@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+)
+
+// HeaderRequestId is the header with id of request, that correlates logs of app and API
+const HeaderRequestId = "X-Request-ID"
+
+// maxRequestIdLen limits size of id sent by client
+const maxRequestIdLen = 128
+
+// WithRequestId sets id of request into context and response headers
+// Id sent by client is used if it's valid, otherwise new one is generated
+func WithRequestId(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(HeaderRequestId)
+		if !validRequestId(id) {
+			id = newRequestId()
+		}
+
+		w.Header().Set(HeaderRequestId, id)
+
+		next.ServeHTTP(w, r.WithContext(logger.NewCtxWithRequestId(r.Context(), id)))
+	})
+}
+
+// validRequestId checks that id is not empty, not too long and has only printable ascii characters
+func validRequestId(id string) bool {
+	if id == "" || len(id) > maxRequestIdLen {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newRequestId generates random id of 32 hex characters
+func newRequestId() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
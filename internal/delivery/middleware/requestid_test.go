@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+)
+
+func TestWithRequestId(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		wantSame bool
+	}{
+		{
+			name:     "client id",
+			header:   "TestRequestId",
+			wantSame: true,
+		},
+		{
+			name: "generated",
+		},
+		{
+			name:   "invalid id",
+			header: "Test Request\tId",
+		},
+		{
+			name:   "too long id",
+			header: strings.Repeat("a", maxRequestIdLen+1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ctxId string
+
+			handler := WithRequestId(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctxId = logger.RequestId(r.Context())
+			}))
+
+			req := httptest.NewRequest("GET", "/songs", nil)
+			if tt.header != "" {
+				req.Header.Set(HeaderRequestId, tt.header)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			respId := w.Header().Get(HeaderRequestId)
+			if respId == "" || respId != ctxId {
+				t.Fatalf("error: id of context %q and response %q must be the same", ctxId, respId)
+			}
+
+			if (respId == tt.header) != tt.wantSame {
+				t.Fatalf("error: unexpected id %q for header %q", respId, tt.header)
+			}
+		})
+	}
+}
+
+func TestWithLogging(t *testing.T) {
+	var buf bytes.Buffer
+
+	log := slog.New(slog.NewTextHandler(&buf, nil))
+
+	handler := WithRequestId(WithLogging(log, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("TestBody"))
+	})))
+
+	req := httptest.NewRequest("POST", "/songs", nil)
+	req.Header.Set(HeaderRequestId, "TestRequestId")
+	req.Header.Set("User-Agent", "TestAgent")
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	for _, want := range []string{"status=201", "bytes=8", "userAgent=TestAgent", "requestId=TestRequestId", "duration="} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("error: access log must contain %q, but got %s", want, buf.String())
+		}
+	}
+}
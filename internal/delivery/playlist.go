@@ -0,0 +1,283 @@
+package delivery
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/internal/service"
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+)
+
+type PlaylistHandler struct {
+	log     *slog.Logger
+	service service.PlaylistServiceIface
+}
+
+func NewPlaylistHandler(l *slog.Logger, s service.PlaylistServiceIface) *PlaylistHandler {
+	return &PlaylistHandler{
+		log:     l,
+		service: s,
+	}
+}
+
+// Create creates a new playlist
+// @Summary Create a new playlist
+// @Description Creates a new empty playlist with the given name
+// @Tags playlists
+// @Accept  json
+// @Produce  json
+// @Param playlist body models.Playlist true "Playlist details"
+// @Success 200 {object} models.Playlist "Created playlist"
+// @Failure 400 {object} Response "Invalid input"
+// @Failure 500 {object} Response "Failed to create playlist"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /playlists [post]
+func (h *PlaylistHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var playlist models.Playlist
+
+	if err := json.NewDecoder(r.Body).Decode(&playlist); err != nil {
+		h.response(w, r, Error("Can't decode json body"), http.StatusBadRequest)
+		return
+	}
+
+	if playlist.Name == "" {
+		h.response(w, r, Error("name must not be empty"), http.StatusBadRequest)
+		return
+	}
+
+	ctx := logger.NewCtxWithLog(r.Context(), h.log)
+
+	res, err := h.service.Create(ctx, playlist.Name)
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), "input", playlist.LogValue())
+
+		h.response(w, r, Error("Can't create playlist"), http.StatusInternalServerError)
+		return
+	}
+
+	h.response(w, r, Ok([]models.Playlist{res}), http.StatusOK)
+}
+
+// Get returns a playlist with paginated songs
+// @Summary Get playlist
+// @Description Returns playlist with songs ordered by position and paginated
+// @Tags playlists
+// @Produce  json
+// @Param id path int true "Playlist Id"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Success 200 {object} models.Playlist "Playlist with songs"
+// @Failure 400 {object} Response "Invalid playlist Id or pagination parameters"
+// @Failure 404 {object} Response "Playlist not found"
+// @Failure 500 {object} Response "Failed to get playlist"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /playlists/{id} [get]
+func (h *PlaylistHandler) Get(w http.ResponseWriter, r *http.Request) {
+	var filters models.GetPlaylistFilters
+
+	if err := filters.SetQueryId(r); err != nil {
+		h.response(w, r, Error("id must be int"), http.StatusBadRequest)
+		return
+	}
+
+	if err := filters.SetQueryData(r); err != nil {
+		h.response(w, r, Error("limit and offset must be int"), http.StatusBadRequest)
+		return
+	}
+
+	ctx := logger.NewCtxWithLog(r.Context(), h.log)
+
+	playlist, ok, err := h.service.Get(ctx, filters)
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), "input", filters.LogValue())
+
+		h.response(w, r, Error("Can't get playlist"), http.StatusInternalServerError)
+		return
+	}
+
+	if !ok {
+		h.response(w, r, Error("Playlist not exists"), http.StatusNotFound)
+		return
+	}
+
+	h.response(w, r, Ok([]models.Playlist{playlist}), http.StatusOK)
+}
+
+// Delete deletes a playlist by Id
+// @Summary Delete a playlist
+// @Description Deletes a playlist with the given Id, songs stay in the library
+// @Tags playlists
+// @Param id path int true "Playlist Id"
+// @Success 204 {object} Response "Playlist deleted successfully"
+// @Failure 400 {object} Response "Invalid playlist Id"
+// @Failure 404 {object} Response "Playlist not found"
+// @Failure 500 {object} Response "Failed to delete playlist"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /playlists/{id} [delete]
+func (h *PlaylistHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	var playlist models.Playlist
+
+	if err := playlist.SetQueryId(r); err != nil {
+		h.response(w, r, Error("id must be int"), http.StatusBadRequest)
+		return
+	}
+
+	ctx := logger.NewCtxWithLog(r.Context(), h.log)
+
+	ok, err := h.service.Delete(ctx, playlist.Id)
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), "input", playlist.LogValue())
+
+		h.response(w, r, Error("Can't delete playlist"), http.StatusInternalServerError)
+		return
+	}
+
+	if !ok {
+		h.response(w, r, Error("Playlist not exists"), http.StatusNotFound)
+		return
+	}
+
+	h.response(w, r, Ok(nil), http.StatusNoContent)
+}
+
+// AddSong adds a song into playlist
+// @Summary Add song to playlist
+// @Description Inserts a song at the given position, zero or out of range position appends song to the end
+// @Tags playlists
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Playlist Id"
+// @Param item body models.PlaylistItem true "Song Id and position"
+// @Success 200 {object} Response "Song added successfully"
+// @Failure 400 {object} Response "Invalid input"
+// @Failure 404 {object} Response "Playlist or song not found"
+// @Failure 500 {object} Response "Failed to add song"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /playlists/{id}/songs [post]
+func (h *PlaylistHandler) AddSong(w http.ResponseWriter, r *http.Request) {
+	var item models.PlaylistItem
+
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		h.response(w, r, Error("Can't decode json body"), http.StatusBadRequest)
+		return
+	}
+
+	if err := item.SetQueryData(r); err != nil {
+		h.response(w, r, Error("id must be int"), http.StatusBadRequest)
+		return
+	}
+
+	ctx := logger.NewCtxWithLog(r.Context(), h.log)
+
+	ok, err := h.service.AddSong(ctx, item)
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), "input", item.LogValue())
+
+		h.response(w, r, Error("Can't add song to playlist"), http.StatusInternalServerError)
+		return
+	}
+
+	if !ok {
+		h.response(w, r, Error("Playlist or song not exists"), http.StatusNotFound)
+		return
+	}
+
+	h.response(w, r, Ok(nil), http.StatusOK)
+}
+
+// MoveSong moves a song inside playlist
+// @Summary Reorder playlist
+// @Description Moves a song from the position in path to the position in body, out of range position moves song to the end
+// @Tags playlists
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Playlist Id"
+// @Param position path int true "Current song position"
+// @Param move body models.PlaylistMove true "New song position"
+// @Success 200 {object} Response "Song moved successfully"
+// @Failure 400 {object} Response "Invalid input"
+// @Failure 404 {object} Response "Playlist or position not found"
+// @Failure 500 {object} Response "Failed to move song"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /playlists/{id}/songs/{position} [put]
+func (h *PlaylistHandler) MoveSong(w http.ResponseWriter, r *http.Request) {
+	var move models.PlaylistMove
+
+	if err := json.NewDecoder(r.Body).Decode(&move); err != nil {
+		h.response(w, r, Error("Can't decode json body"), http.StatusBadRequest)
+		return
+	}
+
+	if err := move.SetQueryData(r); err != nil {
+		h.response(w, r, Error("id and position must be int"), http.StatusBadRequest)
+		return
+	}
+
+	ctx := logger.NewCtxWithLog(r.Context(), h.log)
+
+	ok, err := h.service.MoveSong(ctx, move)
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), "input", move.LogValue())
+
+		h.response(w, r, Error("Can't move song in playlist"), http.StatusInternalServerError)
+		return
+	}
+
+	if !ok {
+		h.response(w, r, Error("Playlist or position not exists"), http.StatusNotFound)
+		return
+	}
+
+	h.response(w, r, Ok(nil), http.StatusOK)
+}
+
+// RemoveSong removes a song from playlist
+// @Summary Remove song from playlist
+// @Description Removes a song at the given position, next songs are shifted
+// @Tags playlists
+// @Param id path int true "Playlist Id"
+// @Param position path int true "Song position"
+// @Success 204 {object} Response "Song removed successfully"
+// @Failure 400 {object} Response "Invalid playlist Id or position"
+// @Failure 404 {object} Response "Playlist or position not found"
+// @Failure 500 {object} Response "Failed to remove song"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /playlists/{id}/songs/{position} [delete]
+func (h *PlaylistHandler) RemoveSong(w http.ResponseWriter, r *http.Request) {
+	var item models.PlaylistItem
+
+	if err := item.SetQueryData(r); err != nil {
+		h.response(w, r, Error("id and position must be int"), http.StatusBadRequest)
+		return
+	}
+
+	ctx := logger.NewCtxWithLog(r.Context(), h.log)
+
+	ok, err := h.service.RemoveSong(ctx, item)
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), "input", item.LogValue())
+
+		h.response(w, r, Error("Can't remove song from playlist"), http.StatusInternalServerError)
+		return
+	}
+
+	if !ok {
+		h.response(w, r, Error("Playlist or position not exists"), http.StatusNotFound)
+		return
+	}
+
+	h.response(w, r, Ok(nil), http.StatusNoContent)
+}
+
+// response send's response and log's it
+func (h *PlaylistHandler) response(w http.ResponseWriter, req *http.Request, res Response, status int) {
+	respond(requestLog(h.log, req), w, res, status)
+}
@@ -0,0 +1,242 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/internal/service/mocks"
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+	"github.com/s3nn1k/ef-mob-task/pkg/test"
+)
+
+func TestCreatePlaylist(t *testing.T) {
+	mock := mocks.NewPlaylistServiceIface(t)
+
+	playlist := models.Playlist{
+		Id:   1,
+		Name: "TestPlaylist",
+	}
+
+	log := logger.NewTextLogger("")
+
+	mock.On("Create", logger.NewCtxWithLog(context.Background(), log), playlist.Name).
+		Return(playlist, nil)
+
+	testCases := []test.TestCase{
+		{
+			Name:       "success",
+			Body:       `{"name":"TestPlaylist"}`,
+			WantStatus: 200,
+			WantRes:    `{"status":"Ok","result":[{"id":1,"name":"TestPlaylist"}]}`,
+		},
+		{
+			Name:       "emptyName",
+			Body:       `{}`,
+			WantStatus: 400,
+			WantRes:    `{"status":"Error","error":"name must not be empty"}`,
+		},
+		{
+			Name:       "wrongBody",
+			WantStatus: 400,
+			WantRes:    `{"status":"Error","error":"Can't decode json body"}`,
+		},
+	}
+
+	handler := NewPlaylistHandler(log, mock)
+
+	router := http.NewServeMux()
+
+	router.HandleFunc("POST /playlists", http.HandlerFunc(handler.Create))
+
+	for _, testCase := range testCases {
+		testCase.Url = "/playlists"
+		testCase.Method = "POST"
+
+		test.TestEndpoint(t, router, testCase)
+	}
+}
+
+func TestGetPlaylist(t *testing.T) {
+	mock := mocks.NewPlaylistServiceIface(t)
+
+	filters := models.GetPlaylistFilters{
+		Id:     1,
+		Limit:  1,
+		Offset: 1,
+	}
+
+	playlist := models.Playlist{
+		Id:   1,
+		Name: "TestPlaylist",
+		Songs: []models.PlaylistSong{
+			{
+				Position: 2,
+				Song: models.Song{
+					Id:    3,
+					Song:  "TestSong",
+					Group: "TestGroup",
+					Text:  "TestText",
+					Link:  "TestLink",
+					Date:  "TestDate",
+				},
+			},
+		},
+	}
+
+	log := logger.NewTextLogger("")
+
+	mock.On("Get", logger.NewCtxWithLog(context.Background(), log), filters).
+		Return(playlist, true, nil)
+
+	testCases := []test.TestCase{
+		{
+			Name:       "success",
+			Url:        "/playlists/1?limit=1&offset=1",
+			WantStatus: 200,
+			WantRes:    `{"status":"Ok","result":[{"id":1,"name":"TestPlaylist","songs":[{"position":2,"id":3,"song":"TestSong","group":"TestGroup","text":"TestText","link":"TestLink","releaseDate":"TestDate"}]}]}`,
+		},
+		{
+			Name:       "invalid id",
+			Url:        "/playlists/one",
+			WantStatus: 400,
+			WantRes:    `{"status":"Error","error":"id must be int"}`,
+		},
+		{
+			Name:       "invalid limit",
+			Url:        "/playlists/1?limit=one",
+			WantStatus: 400,
+			WantRes:    `{"status":"Error","error":"limit and offset must be int"}`,
+		},
+	}
+
+	handler := NewPlaylistHandler(log, mock)
+
+	router := http.NewServeMux()
+
+	router.HandleFunc("GET /playlists/{id}", http.HandlerFunc(handler.Get))
+
+	for _, testCase := range testCases {
+		testCase.Method = "GET"
+
+		test.TestEndpoint(t, router, testCase)
+	}
+}
+
+func TestAddPlaylistSong(t *testing.T) {
+	mock := mocks.NewPlaylistServiceIface(t)
+
+	item := models.PlaylistItem{
+		PlaylistId: 1,
+		SongId:     3,
+		Position:   2,
+	}
+
+	log := logger.NewTextLogger("")
+
+	mock.On("AddSong", logger.NewCtxWithLog(context.Background(), log), item).
+		Return(true, nil)
+
+	testCases := []test.TestCase{
+		{
+			Name:       "success",
+			Url:        "/playlists/1/songs",
+			Body:       `{"songId":3,"position":2}`,
+			WantStatus: 200,
+			WantRes:    `{"status":"Ok"}`,
+		},
+		{
+			Name:       "invalid id",
+			Url:        "/playlists/one/songs",
+			Body:       `{}`,
+			WantStatus: 400,
+			WantRes:    `{"status":"Error","error":"id must be int"}`,
+		},
+	}
+
+	handler := NewPlaylistHandler(log, mock)
+
+	router := http.NewServeMux()
+
+	router.HandleFunc("POST /playlists/{id}/songs", http.HandlerFunc(handler.AddSong))
+
+	for _, testCase := range testCases {
+		testCase.Method = "POST"
+
+		test.TestEndpoint(t, router, testCase)
+	}
+}
+
+func TestMovePlaylistSong(t *testing.T) {
+	mock := mocks.NewPlaylistServiceIface(t)
+
+	move := models.PlaylistMove{
+		PlaylistId: 1,
+		From:       3,
+		To:         1,
+	}
+
+	log := logger.NewTextLogger("")
+
+	mock.On("MoveSong", logger.NewCtxWithLog(context.Background(), log), move).
+		Return(false, nil)
+
+	testCase := test.TestCase{
+		Name:       "fail",
+		Url:        "/playlists/1/songs/3",
+		Method:     "PUT",
+		Body:       `{"position":1}`,
+		WantStatus: 404,
+		WantRes:    `{"status":"Error","error":"Playlist or position not exists"}`,
+	}
+
+	handler := NewPlaylistHandler(log, mock)
+
+	router := http.NewServeMux()
+
+	router.HandleFunc("PUT /playlists/{id}/songs/{position}", http.HandlerFunc(handler.MoveSong))
+
+	test.TestEndpoint(t, router, testCase)
+}
+
+func TestRemovePlaylistSong(t *testing.T) {
+	mock := mocks.NewPlaylistServiceIface(t)
+
+	item := models.PlaylistItem{
+		PlaylistId: 1,
+		Position:   2,
+	}
+
+	log := logger.NewTextLogger("")
+
+	mock.On("RemoveSong", logger.NewCtxWithLog(context.Background(), log), item).
+		Return(true, nil)
+
+	testCases := []test.TestCase{
+		{
+			Name:       "success",
+			Url:        "/playlists/1/songs/2",
+			WantStatus: 204,
+			WantRes:    `{"status":"Ok"}`,
+		},
+		{
+			Name:       "invalid position",
+			Url:        "/playlists/1/songs/two",
+			WantStatus: 400,
+			WantRes:    `{"status":"Error","error":"id and position must be int"}`,
+		},
+	}
+
+	handler := NewPlaylistHandler(log, mock)
+
+	router := http.NewServeMux()
+
+	router.HandleFunc("DELETE /playlists/{id}/songs/{position}", http.HandlerFunc(handler.RemoveSong))
+
+	for _, testCase := range testCases {
+		testCase.Method = "DELETE"
+
+		test.TestEndpoint(t, router, testCase)
+	}
+}
@@ -2,10 +2,16 @@ package delivery
 
 import (
 	"encoding/json"
+	"fmt"
 	"log/slog"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
 )
 
 // Statuses that will return to user with response in json body
@@ -14,32 +20,85 @@ const (
 	statusErr = "Error"
 )
 
+// Media types of response body
+const (
+	mediaJson     = "application/json"
+	mediaText     = "text/plain"
+	mediaMarkdown = "text/markdown"
+)
+
 // type Response represents json body of response
 type Response struct {
 	Status  string `json:"status"`
 	Message string `json:"error,omitempty"`
 	Result  any    `json:"result,omitempty"`
+
+	// doc is used instead of json body if client accepts text
+	doc document
 }
 
-// AsLogValue represents Response struct as slog.Value
+// WithDocument returns response that could be sent as plain text or markdown document
+func (r Response) WithDocument(doc document) Response {
+	r.doc = doc
+	return r
+}
+
+// LogValue represents Response struct as slog.Value
 // Used for logging
-func (r *Response) AsLogValue() slog.Value {
+func (r *Response) LogValue() slog.Value {
 	var logValues []slog.Value
 
-	songs, ok := r.Result.([]models.Song)
-	if !ok {
-		verses, ok := r.Result.([]string)
-		if !ok {
-			logValues = append(logValues, slog.AnyValue(r.Result))
-		} else {
-			for _, verse := range verses {
-				logValues = append(logValues, slog.StringValue(verse))
-			}
+	switch result := r.Result.(type) {
+	case []models.Song:
+		logValues = append(logValues, logger.List(result).LogValue())
+	case []models.PartialSong:
+		songs := make([]models.Song, 0, len(result))
+		for _, partial := range result {
+			songs = append(songs, partial.Song)
+		}
+
+		logValues = append(logValues, logger.List(songs).LogValue())
+	case []models.Playlist:
+		logValues = append(logValues, logger.List(result).LogValue())
+	case []models.Section:
+		logValues = append(logValues, logger.ListFunc(result, func(section models.Section) slog.Value {
+			return slog.GroupValue(
+				slog.String("type", section.Type),
+				slog.String("label", section.Label),
+				logger.Text("text", section.Text),
+			)
+		}).LogValue())
+	case []models.Verse:
+		logValues = append(logValues, logger.ListFunc(result, func(verse models.Verse) slog.Value {
+			return slog.GroupValue(
+				logger.Text("text", verse.Text),
+				slog.Int64("start", verse.Start),
+				slog.Int64("end", verse.End),
+			)
+		}).LogValue())
+	case []models.Lyrics:
+		for _, lyrics := range result {
+			logValues = append(logValues, slog.GroupValue(
+				slog.Int("id", lyrics.Id),
+				slog.Int("lines", len(lyrics.Lines)),
+			))
 		}
-	} else {
-		for _, song := range songs {
-			logValues = append(logValues, song.AsLogValue())
+	case []models.Translation:
+		logValues = append(logValues, logger.List(result).LogValue())
+	case []models.BatchResult:
+		for _, res := range result {
+			logValues = append(logValues, res.LogValue())
 		}
+	case []models.ImportReport:
+		for _, report := range result {
+			logValues = append(logValues, report.LogValue())
+		}
+	case []string:
+		logValues = append(logValues, logger.ListFunc(result, func(verse string) slog.Value {
+			return logger.Text("verse", verse).Value
+		}).LogValue())
+	default:
+		logValues = append(logValues, slog.AnyValue(r.Result))
 	}
 
 	return slog.GroupValue(
@@ -66,20 +125,180 @@ func Error(msg string) Response {
 }
 
 // response send's response and log's it
-func (h *Handler) response(w http.ResponseWriter, r Response, status int) {
+// Response with document is sent as plain text or markdown if client prefers it by Accept header, json is the default
+func (h *Handler) response(w http.ResponseWriter, req *http.Request, res Response, status int) {
+	log := requestLog(h.log, req)
+
+	if res.doc == nil {
+		respond(log, w, res, status)
+		return
+	}
+
+	w.Header().Add("Vary", "Accept")
+
+	var data string
+	var err error
+
+	mt := negotiate(req.Header.Get("Accept"), mediaJson, mediaText, mediaMarkdown)
+
+	switch mt {
+	case mediaText:
+		data = res.doc.Text()
+	case mediaMarkdown:
+		data, err = res.doc.Markdown()
+	default:
+		respond(log, w, res, status)
+		return
+	}
+
+	if err != nil {
+		msg := "Can't render document"
+
+		log.Error(msg+": "+err.Error(), "input", res.LogValue())
+
+		respond(log, w, Error(msg), http.StatusInternalServerError)
+		return
+	}
+
+	h.text(w, req, mt+"; charset=utf-8", []byte(data), status)
+}
+
+// requestLog returns logger with ids of request and it's trace, they are set into context by middlewares
+func requestLog(log *slog.Logger, req *http.Request) *slog.Logger {
+	return logger.LogWith(req.Context(), log)
+}
+
+// respond send's response and log's it with the given logger
+// Used by all handlers to keep the same response format
+func respond(log *slog.Logger, w http.ResponseWriter, r Response, status int) {
 	data, err := json.Marshal(r)
 	if err != nil {
 		msg := "Can't marshal response"
 
-		h.log.Error(msg+": "+err.Error(), "input", r.AsLogValue())
+		log.Error(msg+": "+err.Error(), "input", r.LogValue())
 
 		r = Error(msg)
 		status = http.StatusInternalServerError
 	}
 
-	h.log.Info("Response", slog.Any("response", r.AsLogValue()), slog.Int("status", status))
+	log.Info("Response", slog.Any("response", r.LogValue()), slog.Int("status", status))
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	w.Write(data)
 }
+
+// text send's response with the given content type and log's it
+func (h *Handler) text(w http.ResponseWriter, req *http.Request, contentType string, data []byte, status int) {
+	requestLog(h.log, req).Info("Response", slog.String("contentType", contentType), slog.Int("size", len(data)), slog.Int("status", status))
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// negotiate returns the offer that client prefers most by Accept header
+// The first offer is returned if header is empty or none of offers are acceptable
+func negotiate(accept string, offers ...string) string {
+	best, bestQ := offers[0], 0.0
+
+	if strings.TrimSpace(accept) == "" {
+		return best
+	}
+
+	for _, offer := range offers {
+		// Quality of the most specific matched range is used
+		q, specificity := 0.0, -1
+
+		for _, part := range strings.Split(accept, ",") {
+			mt, params, err := mime.ParseMediaType(part)
+			if err != nil {
+				continue
+			}
+
+			rangeQ := 1.0
+			if val, ok := params["q"]; ok {
+				if rangeQ, err = strconv.ParseFloat(val, 64); err != nil {
+					continue
+				}
+			}
+
+			var spec int
+
+			switch {
+			case mt == offer:
+				spec = 2
+			case mt == "*/*":
+				spec = 0
+			case strings.HasSuffix(mt, "/*") && strings.HasPrefix(offer, strings.TrimSuffix(mt, "*")):
+				spec = 1
+			default:
+				continue
+			}
+
+			if spec > specificity {
+				q, specificity = rangeQ, spec
+			}
+		}
+
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+
+	return best
+}
+
+// mediaType returns media type of Content-Type header without parameters
+func mediaType(contentType string) string {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+
+	return mt
+}
+
+// clearDeadlines removes read and write deadlines of server timeout from request
+// Used by handlers that import and export whole files, errors are ignored as not every writer supports deadlines
+func clearDeadlines(w http.ResponseWriter) {
+	rc := http.NewResponseController(w)
+
+	rc.SetReadDeadline(time.Time{})
+	rc.SetWriteDeadline(time.Time{})
+}
+
+// exportStatusTrailer is trailer with result of export, set to "error" if file is truncated
+const exportStatusTrailer = "X-Export-Status"
+
+// type streamWriter writes file into response
+// Headers are written on the first write, so error response could be sent until then
+// After that result is reported in X-Export-Status trailer
+type streamWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (s *streamWriter) start() {
+	s.started = true
+
+	s.w.Header().Set("Content-Type", s.contentType)
+	s.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", s.filename))
+	s.w.Header().Set("Trailer", exportStatusTrailer)
+	s.w.WriteHeader(http.StatusOK)
+}
+
+// finish sets status of export into trailer
+func (s *streamWriter) finish(status string) {
+	s.w.Header().Set(exportStatusTrailer, status)
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	if !s.started {
+		s.start()
+	}
+
+	return s.w.Write(p)
+}
@@ -0,0 +1,43 @@
+package delivery
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+)
+
+func TestResponseLogValue(t *testing.T) {
+	defer logger.SetPolicy(logger.Policy{})
+
+	logger.SetPolicy(logger.Policy{MaxItems: 2})
+
+	results := []struct {
+		name   string
+		result any
+	}{
+		{name: "verses", result: []string{"first", "second", "third"}},
+		{name: "timed verses", result: []models.Verse{{Text: "first"}, {Text: "second"}, {Text: "third"}}},
+		{name: "sections", result: []models.Section{{Text: "first"}, {Text: "second"}, {Text: "third"}}},
+		{name: "translations", result: []models.Translation{{Text: "first"}, {Text: "second"}, {Text: "third"}}},
+		{name: "playlists", result: []models.Playlist{{Name: "first"}, {Name: "second"}, {Name: "third"}}},
+		{name: "playlist songs", result: []models.Playlist{{Songs: []models.PlaylistSong{
+			{Song: models.Song{Song: "first"}},
+			{Song: models.Song{Song: "second"}},
+			{Song: models.Song{Song: "third"}},
+		}}}},
+	}
+
+	for _, tt := range results {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Ok(tt.result)
+
+			got := res.LogValue().Resolve().String()
+
+			if !strings.Contains(got, "count=3") || !strings.Contains(got, "second") || strings.Contains(got, "third") {
+				t.Fatalf("error: want total count and only two items, but got %s", got)
+			}
+		})
+	}
+}
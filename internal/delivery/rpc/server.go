@@ -0,0 +1,273 @@
+package rpc
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	songsv1 "github.com/s3nn1k/ef-mob-task/api/songs/v1"
+	"github.com/s3nn1k/ef-mob-task/internal/delivery/middleware"
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/internal/service"
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// defaultLimit is the page size if limit is not set, the same as in REST API
+const defaultLimit = 10
+
+// Roles are the roles required by methods, the same as by REST routes
+var Roles = map[string]string{
+	songsv1.SongService_CreateSong_FullMethodName:  models.ScopeWrite,
+	songsv1.SongService_UpdateSong_FullMethodName:  models.ScopeWrite,
+	songsv1.SongService_ListSongs_FullMethodName:   models.ScopeRead,
+	songsv1.SongService_ExportSongs_FullMethodName: models.ScopeRead,
+	songsv1.SongService_GetVerses_FullMethodName:   models.ScopeRead,
+	songsv1.SongService_DeleteSong_FullMethodName:  models.ScopeWrite,
+}
+
+// Routes are REST routes of methods, calls are limited with their limits and share buckets with them
+var Routes = map[string]string{
+	songsv1.SongService_CreateSong_FullMethodName:  "POST /songs",
+	songsv1.SongService_UpdateSong_FullMethodName:  "PUT /songs/{id}",
+	songsv1.SongService_ListSongs_FullMethodName:   "GET /songs",
+	songsv1.SongService_ExportSongs_FullMethodName: "GET /songs/export",
+	songsv1.SongService_GetVerses_FullMethodName:   "GET /songs/{id}",
+	songsv1.SongService_DeleteSong_FullMethodName:  "DELETE /songs/{id}",
+}
+
+// type Server implements gRPC SongService on top of the same service as REST handlers
+type Server struct {
+	songsv1.UnimplementedSongServiceServer
+
+	log     *slog.Logger
+	service service.ServiceIface
+}
+
+func NewServer(l *slog.Logger, s service.ServiceIface) *Server {
+	return &Server{
+		log:     l,
+		service: s,
+	}
+}
+
+// NewGRPCServer creates gRPC server with SongService
+// Calls are logged and get id of request, they are authenticated if auth is not nil and limited if limiter is not nil.
+// Calls are limited by remote address before auth and by principal after it, the same as REST routes
+func NewGRPCServer(log *slog.Logger, srv *Server, auth *middleware.Auth, limiter *middleware.RateLimiter, opts ...grpc.ServerOption) *grpc.Server {
+	unary := []grpc.UnaryServerInterceptor{middleware.UnaryRequestId, middleware.UnaryLogging(log)}
+	stream := []grpc.StreamServerInterceptor{middleware.StreamRequestId, middleware.StreamLogging(log)}
+
+	if auth != nil {
+		if limiter != nil {
+			unary = append(unary, limiter.UnaryAddr)
+			stream = append(stream, limiter.StreamAddr)
+		}
+
+		unary = append(unary, auth.Unary(Roles))
+		stream = append(stream, auth.Stream(Roles))
+	}
+
+	if limiter != nil {
+		unary = append(unary, limiter.Unary(Routes))
+		stream = append(stream, limiter.Stream(Routes))
+	}
+
+	opts = append(opts, grpc.ChainUnaryInterceptor(unary...), grpc.ChainStreamInterceptor(stream...))
+
+	server := grpc.NewServer(opts...)
+
+	songsv1.RegisterSongServiceServer(server, srv)
+
+	return server
+}
+
+func (s *Server) CreateSong(ctx context.Context, req *songsv1.CreateSongRequest) (*songsv1.Song, error) {
+	ctx = logger.NewCtxWithLog(ctx, s.log)
+
+	song, err := s.service.Create(ctx, req.GetSong(), req.GetGroup())
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), slog.String("song", req.GetSong()), slog.String("group", req.GetGroup()))
+
+		return nil, errorStatus(http.StatusInternalServerError, "Can't create song")
+	}
+
+	return toProto(song), nil
+}
+
+func (s *Server) UpdateSong(ctx context.Context, req *songsv1.UpdateSongRequest) (*emptypb.Empty, error) {
+	if req.GetSong() == nil {
+		return nil, errorStatus(http.StatusBadRequest, "song is required")
+	}
+
+	song := fromProto(req.GetSong())
+
+	ctx = logger.NewCtxWithLog(ctx, s.log)
+
+	ok, err := s.service.Update(ctx, song)
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), "input", song.LogValue())
+
+		return nil, errorStatus(http.StatusInternalServerError, "Can't update song")
+	}
+
+	if !ok {
+		return nil, errorStatus(http.StatusNotFound, "Song not exists")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) ListSongs(req *songsv1.ListSongsRequest, stream grpc.ServerStreamingServer[songsv1.Song]) error {
+	filters := models.GetFilters{
+		Limit:  int(req.GetLimit()),
+		Offset: int(req.GetOffset()),
+		Id:     int(req.GetId()),
+		Song:   req.GetSong(),
+		Group:  req.GetGroup(),
+		Date:   req.GetReleaseDate(),
+	}
+
+	if filters.Limit < 1 {
+		filters.Limit = defaultLimit
+	}
+
+	if filters.Offset < 1 {
+		filters.Offset = 0
+	}
+
+	ctx := logger.NewCtxWithLog(stream.Context(), s.log)
+
+	songs, err := s.service.GetAll(ctx, filters)
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), "input", slog.Any("filters", filters.LogValue()))
+
+		return errorStatus(http.StatusInternalServerError, "Can't get songs")
+	}
+
+	for _, song := range songs {
+		if err := stream.Send(toProto(song)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) ExportSongs(req *songsv1.ExportSongsRequest, stream grpc.ServerStreamingServer[songsv1.Song]) error {
+	filters := models.GetFilters{
+		Id:    int(req.GetId()),
+		Song:  req.GetSong(),
+		Group: req.GetGroup(),
+		Date:  req.GetReleaseDate(),
+	}
+
+	ctx := logger.NewCtxWithLog(stream.Context(), s.log)
+
+	err := s.service.Stream(ctx, filters, func(song models.Song) error {
+		return stream.Send(toProto(song))
+	})
+	if err != nil {
+		// Client has gone, so there is nobody to send error to
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+
+		logger.LogUse(ctx).Error(err.Error(), "input", slog.Any("filters", filters.LogValue()))
+
+		return errorStatus(http.StatusInternalServerError, "Can't export songs")
+	}
+
+	return nil
+}
+
+func (s *Server) GetVerses(ctx context.Context, req *songsv1.GetVersesRequest) (*songsv1.GetVersesResponse, error) {
+	filters := models.GetVersesFilters{
+		Id:     int(req.GetId()),
+		Limit:  int(req.GetLimit()),
+		Offset: int(req.GetOffset()),
+		Type:   req.GetType(),
+	}
+
+	if filters.Limit < 1 {
+		filters.Limit = defaultLimit
+	}
+
+	if filters.Offset < 1 {
+		filters.Offset = 0
+	}
+
+	if filters.Type != "" && !models.IsSectionType(filters.Type) {
+		return nil, errorStatus(http.StatusBadRequest, "Unknown section type")
+	}
+
+	if req.GetLang() != "" {
+		lang, err := models.ParseLang(req.GetLang())
+		if err != nil {
+			return nil, errorStatus(http.StatusBadRequest, "lang must be valid language tag")
+		}
+
+		filters.Lang = lang
+	}
+
+	ctx = logger.NewCtxWithLog(ctx, s.log)
+
+	verses, err := s.service.GetVerses(ctx, filters)
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), "input", slog.Any("filters", filters.LogValue()))
+
+		return nil, errorStatus(http.StatusInternalServerError, "Can't get song")
+	}
+
+	if verses == nil {
+		return nil, errorStatus(http.StatusNotFound, "Empty verses response")
+	}
+
+	return &songsv1.GetVersesResponse{Verses: verses}, nil
+}
+
+func (s *Server) DeleteSong(ctx context.Context, req *songsv1.DeleteSongRequest) (*emptypb.Empty, error) {
+	ctx = logger.NewCtxWithLog(ctx, s.log)
+
+	ok, err := s.service.Delete(ctx, int(req.GetId()))
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), "input", slog.Int64("id", req.GetId()))
+
+		return nil, errorStatus(http.StatusInternalServerError, "Can't delete song")
+	}
+
+	if !ok {
+		return nil, errorStatus(http.StatusNotFound, "Song not exists")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// errorStatus returns gRPC error with code that matches http status of the same error in REST API
+func errorStatus(httpStatus int, msg string) error {
+	return status.Error(middleware.GRPCCode(httpStatus), msg)
+}
+
+func toProto(song models.Song) *songsv1.Song {
+	return &songsv1.Song{
+		Id:          int64(song.Id),
+		Song:        song.Song,
+		Group:       song.Group,
+		Text:        song.Text,
+		Link:        song.Link,
+		ReleaseDate: song.Date,
+	}
+}
+
+func fromProto(song *songsv1.Song) models.Song {
+	return models.Song{
+		Id:    int(song.GetId()),
+		Song:  song.GetSong(),
+		Group: song.GetGroup(),
+		Text:  song.GetText(),
+		Link:  song.GetLink(),
+		Date:  song.GetReleaseDate(),
+	}
+}
@@ -0,0 +1,314 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	songsv1 "github.com/s3nn1k/ef-mob-task/api/songs/v1"
+	"github.com/s3nn1k/ef-mob-task/internal/config"
+	"github.com/s3nn1k/ef-mob-task/internal/delivery/middleware"
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/internal/service/mocks"
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+	testifymock "github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// type keys authenticates keys from map
+type keys map[string]models.APIKey
+
+func (k keys) Authenticate(ctx context.Context, secret string) (models.APIKey, bool, error) {
+	key, ok := k[secret]
+	return key, ok, nil
+}
+
+// newClient starts gRPC server in memory and returns client of it
+func newClient(t *testing.T, srvc *mocks.ServiceIface, auth *middleware.Auth, limiter *middleware.RateLimiter) songsv1.SongServiceClient {
+	t.Helper()
+
+	log := logger.NewTextLogger("")
+
+	listener := bufconn.Listen(1 << 20)
+
+	server := NewGRPCServer(log, NewServer(log, srvc), auth, limiter)
+
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { conn.Close() })
+
+	return songsv1.NewSongServiceClient(conn)
+}
+
+func TestCreateSong(t *testing.T) {
+	mock := mocks.NewServiceIface(t)
+
+	mock.On("Create", testifymock.Anything, "TestSong", "TestGroup").
+		Return(models.Song{Id: 1, Song: "TestSong", Group: "TestGroup", Date: "16.07.2006"}, nil).Once()
+	mock.On("Create", testifymock.Anything, "Unknown", "TestGroup").
+		Return(models.Song{}, errors.New("not found in API")).Once()
+
+	clnt := newClient(t, mock, nil, nil)
+
+	var header metadata.MD
+
+	song, err := clnt.CreateSong(context.Background(), &songsv1.CreateSongRequest{Song: "TestSong", Group: "TestGroup"}, grpc.Header(&header))
+	if err != nil {
+		t.Fatalf("error not expected while creating song: %s", err)
+	}
+
+	if song.GetId() != 1 || song.GetReleaseDate() != "16.07.2006" {
+		t.Fatalf("error: want created song, but got %v", song)
+	}
+
+	if len(header.Get(middleware.HeaderRequestId)) != 1 {
+		t.Fatalf("error: want request id in response header, but got %v", header)
+	}
+
+	_, err = clnt.CreateSong(context.Background(), &songsv1.CreateSongRequest{Song: "Unknown", Group: "TestGroup"})
+	if status.Code(err) != codes.Internal || status.Convert(err).Message() != "Can't create song" {
+		t.Fatalf("error: want internal error without details, but got %v", err)
+	}
+}
+
+func TestUpdateAndDeleteNotFound(t *testing.T) {
+	mock := mocks.NewServiceIface(t)
+
+	mock.On("Update", testifymock.Anything, models.Song{Id: 2, Song: "TestSong"}).Return(false, nil).Once()
+	mock.On("Delete", testifymock.Anything, 2).Return(false, nil).Once()
+
+	clnt := newClient(t, mock, nil, nil)
+
+	_, err := clnt.UpdateSong(context.Background(), &songsv1.UpdateSongRequest{Song: &songsv1.Song{Id: 2, Song: "TestSong"}})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("error: want not found, but got %v", err)
+	}
+
+	_, err = clnt.UpdateSong(context.Background(), &songsv1.UpdateSongRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("error: want invalid argument, but got %v", err)
+	}
+
+	_, err = clnt.DeleteSong(context.Background(), &songsv1.DeleteSongRequest{Id: 2})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("error: want not found, but got %v", err)
+	}
+}
+
+func TestListAndExportSongs(t *testing.T) {
+	mock := mocks.NewServiceIface(t)
+
+	songs := []models.Song{{Id: 1, Song: "First"}, {Id: 2, Song: "Second"}}
+
+	mock.On("GetAll", testifymock.Anything, models.GetFilters{Limit: 10, Group: "TestGroup"}).Return(songs, nil).Once()
+	mock.On("Stream", testifymock.Anything, models.GetFilters{Group: "TestGroup"}, testifymock.Anything).
+		Return(func(ctx context.Context, filters models.GetFilters, fn func(models.Song) error) error {
+			for _, song := range songs {
+				if err := fn(song); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}).Once()
+
+	clnt := newClient(t, mock, nil, nil)
+
+	recv := func(stream grpc.ServerStreamingClient[songsv1.Song], err error) []string {
+		t.Helper()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var names []string
+
+		for {
+			song, err := stream.Recv()
+			if err == io.EOF {
+				return names
+			}
+
+			if err != nil {
+				t.Fatalf("error not expected while receiving songs: %s", err)
+			}
+
+			names = append(names, song.GetSong())
+		}
+	}
+
+	names := recv(clnt.ListSongs(context.Background(), &songsv1.ListSongsRequest{Group: "TestGroup"}))
+	if len(names) != 2 || names[0] != "First" || names[1] != "Second" {
+		t.Fatalf("error: want all songs of page, but got %v", names)
+	}
+
+	names = recv(clnt.ExportSongs(context.Background(), &songsv1.ExportSongsRequest{Group: "TestGroup"}))
+	if len(names) != 2 || names[0] != "First" || names[1] != "Second" {
+		t.Fatalf("error: want all exported songs, but got %v", names)
+	}
+}
+
+func TestGetVerses(t *testing.T) {
+	mock := mocks.NewServiceIface(t)
+
+	mock.On("GetVerses", testifymock.Anything, models.GetVersesFilters{Id: 1, Limit: 1, Type: models.SectionChorus, Lang: "pt-BR"}).
+		Return([]string{"Chorus"}, nil).Once()
+
+	clnt := newClient(t, mock, nil, nil)
+
+	res, err := clnt.GetVerses(context.Background(), &songsv1.GetVersesRequest{Id: 1, Limit: 1, Type: models.SectionChorus, Lang: "pt-br"})
+	if err != nil {
+		t.Fatalf("error not expected while getting verses: %s", err)
+	}
+
+	if len(res.GetVerses()) != 1 || res.GetVerses()[0] != "Chorus" {
+		t.Fatalf("error: want verses, but got %v", res.GetVerses())
+	}
+
+	_, err = clnt.GetVerses(context.Background(), &songsv1.GetVersesRequest{Id: 1, Type: "solo"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("error: want invalid argument for unknown type, but got %v", err)
+	}
+}
+
+func TestAuth(t *testing.T) {
+	mock := mocks.NewServiceIface(t)
+
+	mock.On("GetAll", testifymock.Anything, testifymock.Anything).Return([]models.Song{}, nil)
+	mock.On("Delete", testifymock.Anything, 1).Return(true, nil).Once()
+
+	auth := middleware.NewAuth(logger.NewTextLogger(""), keys{
+		"sk_reader": {Id: 1, Scope: models.ScopeRead},
+		"sk_writer": {Id: 2, Scope: models.ScopeWrite},
+	}, nil)
+
+	clnt := newClient(t, mock, auth, nil)
+
+	withKey := func(key string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "x-api-key", key)
+	}
+
+	testCases := []struct {
+		name string
+		ctx  context.Context
+		want codes.Code
+	}{
+		{name: "no credentials", ctx: context.Background(), want: codes.Unauthenticated},
+		{name: "invalid key", ctx: withKey("sk_unknown"), want: codes.Unauthenticated},
+		{name: "low scope", ctx: withKey("sk_reader"), want: codes.PermissionDenied},
+		{name: "bearer key", ctx: metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer sk_writer"), want: codes.OK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := clnt.DeleteSong(tc.ctx, &songsv1.DeleteSongRequest{Id: 1})
+			if status.Code(err) != tc.want {
+				t.Fatalf("error: want code %s, but got %v", tc.want, err)
+			}
+		})
+	}
+
+	// Streams are authenticated too
+	stream, err := clnt.ListSongs(context.Background(), &songsv1.ListSongsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := stream.Recv(); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("error: want unauthenticated stream, but got %v", err)
+	}
+
+	stream, err = clnt.ListSongs(withKey("sk_reader"), &songsv1.ListSongsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Fatalf("error: want empty stream, but got %v", err)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	mock := mocks.NewServiceIface(t)
+
+	mock.On("Create", testifymock.Anything, "TestSong", "TestGroup").Return(models.Song{Id: 1}, nil).Once()
+	mock.On("GetAll", testifymock.Anything, testifymock.Anything).Return([]models.Song{}, nil).Once()
+
+	limiter := middleware.NewRateLimiter(config.RateLimit{
+		Default: config.Limit{Count: 100, Period: time.Minute},
+		Routes: map[string]config.Limit{
+			"POST /songs": {Count: 1, Period: time.Minute},
+			"GET /songs":  {Count: 1, Period: time.Minute},
+		},
+		Addr: config.Limit{Count: 2, Period: time.Minute},
+	})
+
+	clnt := newClient(t, mock, nil, limiter)
+
+	req := &songsv1.CreateSongRequest{Song: "TestSong", Group: "TestGroup"}
+
+	if _, err := clnt.CreateSong(context.Background(), req); err != nil {
+		t.Fatalf("error not expected for the first call: %s", err)
+	}
+
+	var header metadata.MD
+
+	_, err := clnt.CreateSong(context.Background(), req, grpc.Header(&header))
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("error: want resource exhausted with limit of route, but got %v", err)
+	}
+
+	if got := header.Get("retry-after"); len(got) != 1 || got[0] != "60" {
+		t.Fatalf("error: want retry-after 60, but got %v", got)
+	}
+
+	// Streams are limited too
+	stream, err := clnt.ListSongs(context.Background(), &songsv1.ListSongsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Fatalf("error: want empty stream, but got %v", err)
+	}
+
+	stream, err = clnt.ListSongs(context.Background(), &songsv1.ListSongsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := stream.Recv(); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("error: want resource exhausted stream, but got %v", err)
+	}
+
+	// Calls with invalid credentials are limited by address before auth
+	auth := middleware.NewAuth(logger.NewTextLogger(""), keys{}, nil)
+
+	clnt = newClient(t, mock, auth, limiter)
+
+	for i, want := range []codes.Code{codes.Unauthenticated, codes.Unauthenticated, codes.ResourceExhausted} {
+		_, err := clnt.DeleteSong(context.Background(), &songsv1.DeleteSongRequest{Id: 1})
+		if status.Code(err) != want {
+			t.Fatalf("error: call %d must get %s, but got %v", i, want, err)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package delivery
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+)
+
+// SetTranslation creates or replaces translation of a Song
+// @Summary Set song translation
+// @Description Creates or replaces translated text of the specified Song, verses are matched with original by index
+// @Tags translations
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Song Id"
+// @Param lang path string true "BCP 47 language tag"
+// @Param translation body models.Translation true "Translated text"
+// @Success 200 {object} Response "Translation saved successfully"
+// @Failure 400 {object} Response "Invalid input"
+// @Failure 404 {object} Response "Song not found"
+// @Failure 500 {object} Response "Failed to save translation"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /songs/{id}/translations/{lang} [put]
+func (h *Handler) SetTranslation(w http.ResponseWriter, r *http.Request) {
+	var translation models.Translation
+
+	if err := json.NewDecoder(r.Body).Decode(&translation); err != nil {
+		h.response(w, r, Error("Can't decode json body"), http.StatusBadRequest)
+		return
+	}
+
+	if err := translation.SetQueryData(r); err != nil {
+		h.response(w, r, Error("id must be int and lang must be valid language tag"), http.StatusBadRequest)
+		return
+	}
+
+	if translation.Text == "" {
+		h.response(w, r, Error("text must not be empty"), http.StatusBadRequest)
+		return
+	}
+
+	ctx := logger.NewCtxWithLog(r.Context(), h.log)
+
+	ok, err := h.service.SetTranslation(ctx, translation)
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), "input", translation.LogValue())
+
+		h.response(w, r, Error("Can't save translation"), http.StatusInternalServerError)
+		return
+	}
+
+	if !ok {
+		h.response(w, r, Error("Song not exists"), http.StatusNotFound)
+		return
+	}
+
+	h.response(w, r, Ok(nil), http.StatusOK)
+}
+
+// GetTranslation returns translation of a Song
+// @Summary Get song translation
+// @Description Returns translated text of the specified Song
+// @Tags translations
+// @Produce  json
+// @Param id path int true "Song Id"
+// @Param lang path string true "BCP 47 language tag"
+// @Success 200 {object} models.Translation "Translation"
+// @Failure 400 {object} Response "Invalid song Id or language"
+// @Failure 404 {object} Response "Translation not found"
+// @Failure 500 {object} Response "Failed to get translation"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /songs/{id}/translations/{lang} [get]
+func (h *Handler) GetTranslation(w http.ResponseWriter, r *http.Request) {
+	var translation models.Translation
+
+	if err := translation.SetQueryData(r); err != nil {
+		h.response(w, r, Error("id must be int and lang must be valid language tag"), http.StatusBadRequest)
+		return
+	}
+
+	ctx := logger.NewCtxWithLog(r.Context(), h.log)
+
+	res, ok, err := h.service.GetTranslation(ctx, translation.Id, translation.Lang)
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), "input", translation.LogValue())
+
+		h.response(w, r, Error("Can't get translation"), http.StatusInternalServerError)
+		return
+	}
+
+	if !ok {
+		h.response(w, r, Error("Translation not exists"), http.StatusNotFound)
+		return
+	}
+
+	h.response(w, r, Ok([]models.Translation{res}), http.StatusOK)
+}
+
+// GetTranslations returns languages of Song translations
+// @Summary Get song translations
+// @Description Returns languages of all translations of the specified Song
+// @Tags translations
+// @Produce  json
+// @Param id path int true "Song Id"
+// @Success 200 {array} string "Array of language tags"
+// @Failure 400 {object} Response "Invalid song Id"
+// @Failure 404 {object} Response "Song not found"
+// @Failure 500 {object} Response "Failed to get translations"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /songs/{id}/translations [get]
+func (h *Handler) GetTranslations(w http.ResponseWriter, r *http.Request) {
+	var translation models.Translation
+
+	if err := translation.SetQueryData(r); err != nil {
+		h.response(w, r, Error("id must be int"), http.StatusBadRequest)
+		return
+	}
+
+	ctx := logger.NewCtxWithLog(r.Context(), h.log)
+
+	langs, err := h.service.GetTranslations(ctx, translation.Id)
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), "input", slog.Int("id", translation.Id))
+
+		h.response(w, r, Error("Can't get translations"), http.StatusInternalServerError)
+		return
+	}
+
+	if langs == nil {
+		h.response(w, r, Error("Song not exists"), http.StatusNotFound)
+		return
+	}
+
+	h.response(w, r, Ok(langs), http.StatusOK)
+}
+
+// DeleteTranslation deletes translation of a Song
+// @Summary Delete song translation
+// @Description Deletes translation of the specified Song in the given language
+// @Tags translations
+// @Param id path int true "Song Id"
+// @Param lang path string true "BCP 47 language tag"
+// @Success 204 {object} Response "Translation deleted successfully"
+// @Failure 400 {object} Response "Invalid song Id or language"
+// @Failure 404 {object} Response "Translation not found"
+// @Failure 500 {object} Response "Failed to delete translation"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /songs/{id}/translations/{lang} [delete]
+func (h *Handler) DeleteTranslation(w http.ResponseWriter, r *http.Request) {
+	var translation models.Translation
+
+	if err := translation.SetQueryData(r); err != nil {
+		h.response(w, r, Error("id must be int and lang must be valid language tag"), http.StatusBadRequest)
+		return
+	}
+
+	ctx := logger.NewCtxWithLog(r.Context(), h.log)
+
+	ok, err := h.service.DeleteTranslation(ctx, translation.Id, translation.Lang)
+	if err != nil {
+		logger.LogUse(ctx).Error(err.Error(), "input", translation.LogValue())
+
+		h.response(w, r, Error("Can't delete translation"), http.StatusInternalServerError)
+		return
+	}
+
+	if !ok {
+		h.response(w, r, Error("Translation not exists"), http.StatusNotFound)
+		return
+	}
+
+	h.response(w, r, Ok(nil), http.StatusNoContent)
+}
@@ -0,0 +1,161 @@
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/internal/service/mocks"
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+	"github.com/s3nn1k/ef-mob-task/pkg/test"
+)
+
+func TestSetTranslation(t *testing.T) {
+	mock := mocks.NewServiceIface(t)
+
+	translation := models.Translation{
+		Id:   1,
+		Lang: "pt-BR",
+		Text: "TestText",
+	}
+
+	log := logger.NewTextLogger("")
+
+	mock.On("SetTranslation", logger.NewCtxWithLog(context.Background(), log), translation).
+		Return(true, nil)
+
+	testCases := []test.TestCase{
+		{
+			Name:       "success",
+			Url:        "/songs/1/translations/pt-br",
+			Body:       `{"text":"TestText"}`,
+			WantStatus: 200,
+			WantRes:    `{"status":"Ok"}`,
+		},
+		{
+			Name:       "invalid lang",
+			Url:        "/songs/1/translations/not_a_tag!",
+			Body:       `{"text":"TestText"}`,
+			WantStatus: 400,
+			WantRes:    `{"status":"Error","error":"id must be int and lang must be valid language tag"}`,
+		},
+		{
+			Name:       "empty text",
+			Url:        "/songs/1/translations/en",
+			Body:       `{}`,
+			WantStatus: 400,
+			WantRes:    `{"status":"Error","error":"text must not be empty"}`,
+		},
+	}
+
+	handler := NewHandler(log, mock)
+
+	router := http.NewServeMux()
+
+	router.HandleFunc("PUT /songs/{id}/translations/{lang}", http.HandlerFunc(handler.SetTranslation))
+
+	for _, testCase := range testCases {
+		testCase.Method = "PUT"
+
+		test.TestEndpoint(t, router, testCase)
+	}
+}
+
+func TestGetTranslations(t *testing.T) {
+	mock := mocks.NewServiceIface(t)
+
+	log := logger.NewTextLogger("")
+
+	mock.On("GetTranslations", logger.NewCtxWithLog(context.Background(), log), 1).
+		Return([]string{"de", "en"}, nil)
+
+	mock.On("GetTranslations", logger.NewCtxWithLog(context.Background(), log), 2).
+		Return(nil, nil)
+
+	testCases := []test.TestCase{
+		{
+			Name:       "success",
+			Url:        "/songs/1/translations",
+			WantStatus: 200,
+			WantRes:    `{"status":"Ok","result":["de","en"]}`,
+		},
+		{
+			Name:       "not exists",
+			Url:        "/songs/2/translations",
+			WantStatus: 404,
+			WantRes:    `{"status":"Error","error":"Song not exists"}`,
+		},
+	}
+
+	handler := NewHandler(log, mock)
+
+	router := http.NewServeMux()
+
+	router.HandleFunc("GET /songs/{id}/translations", http.HandlerFunc(handler.GetTranslations))
+
+	for _, testCase := range testCases {
+		testCase.Method = "GET"
+
+		test.TestEndpoint(t, router, testCase)
+	}
+}
+
+func TestGetTranslation(t *testing.T) {
+	mock := mocks.NewServiceIface(t)
+
+	var buf bytes.Buffer
+
+	log := logger.New(&buf, logger.FormatText, new(slog.LevelVar))
+
+	mock.On("GetTranslation", logger.NewCtxWithLog(context.Background(), log), 1, "de").
+		Return(models.Translation{Id: 1, Lang: "de", Text: "TestText"}, true, nil)
+
+	mock.On("GetTranslation", logger.NewCtxWithLog(context.Background(), log), 2, "de").
+		Return(models.Translation{}, false, nil)
+
+	mock.On("GetTranslation", logger.NewCtxWithLog(context.Background(), log), 3, "de").
+		Return(models.Translation{}, false, errors.New("TestError"))
+
+	testCases := []test.TestCase{
+		{
+			Name:       "success",
+			Url:        "/songs/1/translations/de",
+			WantStatus: 200,
+			WantRes:    `{"status":"Ok","result":[{"id":1,"lang":"de","text":"TestText"}]}`,
+		},
+		{
+			Name:       "not exists",
+			Url:        "/songs/2/translations/de",
+			WantStatus: 404,
+			WantRes:    `{"status":"Error","error":"Translation not exists"}`,
+		},
+		{
+			Name:       "fail",
+			Url:        "/songs/3/translations/de",
+			WantStatus: 500,
+			WantRes:    `{"status":"Error","error":"Can't get translation"}`,
+		},
+	}
+
+	handler := NewHandler(log, mock)
+
+	router := http.NewServeMux()
+
+	router.HandleFunc("GET /songs/{id}/translations/{lang}", http.HandlerFunc(handler.GetTranslation))
+
+	for _, testCase := range testCases {
+		testCase.Method = "GET"
+
+		test.TestEndpoint(t, router, testCase)
+	}
+
+	// Input of request is logged, not the empty result of service
+	if !strings.Contains(buf.String(), "msg=TestError input.id=3 input.lang=de") {
+		t.Fatalf("error: want input of request in error log, but got %s", buf.String())
+	}
+}
@@ -0,0 +1,161 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// type Phase represents step of shutdown, phases are stopped in order of their values
+type Phase int
+
+// Phases of shutdown
+const (
+	// PhaseServers stops accepting connections and drains in-flight requests
+	PhaseServers Phase = iota
+	// PhaseWorkers stops background workers after their context is cancelled
+	PhaseWorkers
+	// PhaseResources closes connections, flushes telemetry and logs
+	PhaseResources
+)
+
+var phaseNames = map[Phase]string{
+	PhaseServers:   "servers",
+	PhaseWorkers:   "workers",
+	PhaseResources: "resources",
+}
+
+func (p Phase) String() string {
+	return phaseNames[p]
+}
+
+// type hook represents stop func of component
+type hook struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+// type Lifecycle stops components of app in order
+// Hooks of one phase are called in reverse order of registration, like deferred calls
+type Lifecycle struct {
+	log     *slog.Logger
+	timeout time.Duration
+
+	mu      sync.Mutex
+	hooks   map[Phase][]hook
+	stopped bool
+
+	// ctx is cancelled when workers must stop
+	ctx     context.Context
+	cancel  context.CancelFunc
+	workers sync.WaitGroup
+}
+
+// New creates lifecycle, every phase of shutdown is limited by timeout
+func New(log *slog.Logger, timeout time.Duration) *Lifecycle {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	l := &Lifecycle{
+		log:     log,
+		timeout: timeout,
+		hooks:   make(map[Phase][]hook),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+
+	l.OnStop(PhaseWorkers, "workers", l.stopWorkers)
+
+	return l
+}
+
+// OnStop registers stop func of component, that is called in the given phase
+func (l *Lifecycle) OnStop(phase Phase, name string, stop func(ctx context.Context) error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.hooks[phase] = append(l.hooks[phase], hook{name: name, stop: stop})
+}
+
+// Go runs background worker until it returns or it's context is cancelled in workers phase
+func (l *Lifecycle) Go(name string, run func(ctx context.Context) error) {
+	l.workers.Add(1)
+
+	go func() {
+		defer l.workers.Done()
+
+		if err := run(l.ctx); err != nil && !errors.Is(err, context.Canceled) {
+			l.log.Error("Worker failed", slog.String("worker", name), slog.String("error", err.Error()))
+		}
+	}()
+}
+
+// stopWorkers cancels context of workers and waits for them
+func (l *Lifecycle) stopWorkers(ctx context.Context) error {
+	l.cancel()
+
+	done := make(chan struct{})
+
+	go func() {
+		l.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stop calls hooks of all phases in order and returns all errors
+// Next phase is started even if the previous one failed, so resources are always released. Stop runs only once
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	l.mu.Lock()
+	if l.stopped {
+		l.mu.Unlock()
+		return nil
+	}
+
+	l.stopped = true
+	hooks := l.hooks
+	l.mu.Unlock()
+
+	var errs []error
+
+	for _, phase := range []Phase{PhaseServers, PhaseWorkers, PhaseResources} {
+		errs = append(errs, l.stopPhase(ctx, phase, hooks[phase]))
+	}
+
+	return errors.Join(errs...)
+}
+
+// stopPhase calls hooks of phase with timeout
+func (l *Lifecycle) stopPhase(ctx context.Context, phase Phase, hooks []hook) error {
+	ctx, cancel := context.WithTimeout(ctx, l.timeout)
+	defer cancel()
+
+	var errs []error
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+
+		start := time.Now()
+
+		log := l.log.With(slog.String("phase", phase.String()), slog.String("component", h.name))
+
+		if err := h.stop(ctx); err != nil {
+			log.Error("Can't stop component", slog.String("error", err.Error()))
+
+			errs = append(errs, fmt.Errorf("stop %s: %w", h.name, err))
+			continue
+		}
+
+		log.Info("Stopped component", slog.Duration("duration", time.Since(start)))
+	}
+
+	return errors.Join(errs...)
+}
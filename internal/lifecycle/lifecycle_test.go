@@ -0,0 +1,103 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+)
+
+func TestStop(t *testing.T) {
+	lc := New(logger.NewTextLogger("error"), time.Second)
+
+	var order []string
+
+	record := func(name string, err error) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			order = append(order, name)
+			return err
+		}
+	}
+
+	lc.OnStop(PhaseResources, "log file", record("log file", nil))
+	lc.OnStop(PhaseResources, "postgres", record("postgres", errors.New("TestError")))
+	lc.OnStop(PhaseServers, "http server", record("http server", nil))
+	lc.OnStop(PhaseServers, "readiness", record("readiness", nil))
+
+	lc.Go("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		order = append(order, "worker")
+		return ctx.Err()
+	})
+
+	err := lc.Stop(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "stop postgres: TestError") {
+		t.Fatalf("error: want error of postgres, but got %v", err)
+	}
+
+	want := []string{"readiness", "http server", "worker", "postgres", "log file"}
+	if strings.Join(order, ",") != strings.Join(want, ",") {
+		t.Fatalf("error: want order %v, but got %v", want, order)
+	}
+
+	if err := lc.Stop(context.Background()); err != nil {
+		t.Fatalf("error: second stop must do nothing, but got %s", err)
+	}
+}
+
+func TestStopTimeout(t *testing.T) {
+	lc := New(logger.NewTextLogger("error"), 10*time.Millisecond)
+
+	closed := false
+
+	lc.OnStop(PhaseResources, "postgres", func(ctx context.Context) error {
+		closed = true
+		return nil
+	})
+
+	lc.OnStop(PhaseServers, "http server", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	err := lc.Stop(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error: want deadline of draining, but got %v", err)
+	}
+
+	if !closed {
+		t.Fatal("error: resources must be closed even if draining failed")
+	}
+}
+
+func TestStopUpstreamAfterServers(t *testing.T) {
+	lc := New(logger.NewTextLogger("error"), time.Second)
+
+	upstream := true
+
+	// Server drains requests that call upstream, so upstream must be running until it's stopped
+	lc.OnStop(PhaseServers, "http server", func(ctx context.Context) error {
+		if !upstream {
+			return errors.New("upstream is stopped before draining")
+		}
+
+		return nil
+	})
+
+	// Upstream is registered after servers, like test API in serve command
+	lc.OnStop(PhaseResources, "test api", func(ctx context.Context) error {
+		upstream = false
+		return nil
+	})
+
+	if err := lc.Stop(context.Background()); err != nil {
+		t.Fatalf("error not expected: %s", err)
+	}
+
+	if upstream {
+		t.Fatal("error: upstream must be stopped")
+	}
+}
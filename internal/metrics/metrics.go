@@ -0,0 +1,176 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/s3nn1k/ef-mob-task/internal/client"
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+)
+
+// namespace is the prefix of all metrics
+const namespace = "songs"
+
+const (
+	// countTimeout is the maximum duration of counting songs while scraping
+	countTimeout = 2 * time.Second
+	// countInterval is the time that count of songs is cached for, so scrapes don't count them every time
+	countInterval = 30 * time.Second
+)
+
+// type Metrics represents registry with metrics of application
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	clientDuration  *prometheus.HistogramVec
+	clientErrors    *prometheus.CounterVec
+}
+
+// New creates registry with go runtime and process metrics
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Count of handled requests by route and status",
+		}, []string{"route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of handled requests by route and status",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "status"}),
+		clientDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "client_request_duration_seconds",
+			Help:      "Duration of requests to external API by operation",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation"}),
+		clientErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "client_errors_total",
+			Help:      "Count of failed requests to external API by operation",
+		}, []string{"operation"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.clientDuration,
+		m.clientErrors,
+	)
+
+	return m
+}
+
+// Handler returns handler that exposes metrics in prometheus format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// ObserveRequest records handled request
+func (m *Metrics) ObserveRequest(route string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+
+	m.requests.WithLabelValues(route, code).Inc()
+	m.requestDuration.WithLabelValues(route, code).Observe(duration.Seconds())
+}
+
+// RegisterPool exposes stats of postgres connection pool
+func (m *Metrics) RegisterPool(pool *pgxpool.Pool) {
+	m.registry.MustRegister(newPoolCollector(pool))
+}
+
+// RegisterSongs exposes count of songs, they are counted on scrape if cached count is older than interval
+func (m *Metrics) RegisterSongs(count func(ctx context.Context) (int, error)) {
+	m.registry.MustRegister(&songsCollector{
+		count:    count,
+		interval: countInterval,
+		now:      time.Now,
+		desc:     prometheus.NewDesc(namespace+"_songs", "Count of songs in library", nil, nil),
+	})
+}
+
+// InstrumentClient returns client that records duration and errors of requests to external API
+func (m *Metrics) InstrumentClient(c client.ClientIface) client.ClientIface {
+	return &instrumentedClient{
+		next:     c,
+		duration: m.clientDuration,
+		errors:   m.clientErrors,
+	}
+}
+
+// type instrumentedClient records metrics of wrapped client
+type instrumentedClient struct {
+	next     client.ClientIface
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+}
+
+func (c *instrumentedClient) GetDetail(ctx context.Context, song string, group string) (models.Song, error) {
+	const operation = "GetDetail"
+
+	start := time.Now()
+
+	res, err := c.next.GetDetail(ctx, song, group)
+
+	c.duration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		c.errors.WithLabelValues(operation).Inc()
+	}
+
+	return res, err
+}
+
+// type songsCollector collects count of songs
+// Count is cached for interval, errors are not cached
+type songsCollector struct {
+	count    func(ctx context.Context) (int, error)
+	interval time.Duration
+	desc     *prometheus.Desc
+
+	mu      sync.Mutex
+	cached  int
+	counted time.Time
+
+	// now is replaced in tests
+	now func() time.Time
+}
+
+func (c *songsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *songsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now := c.now(); c.counted.IsZero() || now.Sub(c.counted) >= c.interval {
+		ctx, cancel := context.WithTimeout(context.Background(), countTimeout)
+		defer cancel()
+
+		count, err := c.count(ctx)
+		if err != nil {
+			ch <- prometheus.NewInvalidMetric(c.desc, err)
+			return
+		}
+
+		c.cached = count
+		c.counted = now
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(c.cached))
+}
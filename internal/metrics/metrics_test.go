@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+)
+
+// type fakeClient returns error for empty song
+type fakeClient struct{}
+
+func (c fakeClient) GetDetail(ctx context.Context, song string, group string) (models.Song, error) {
+	if song == "" {
+		return models.Song{}, errors.New("song is empty")
+	}
+
+	return models.Song{Song: song, Group: group}, nil
+}
+
+func TestMetrics(t *testing.T) {
+	m := New()
+
+	m.ObserveRequest("POST /songs", 201, 10*time.Millisecond)
+	m.ObserveRequest("POST /songs", 201, 20*time.Millisecond)
+	m.ObserveRequest("POST /songs", 429, time.Millisecond)
+
+	if count := testutil.ToFloat64(m.requests.WithLabelValues("POST /songs", "201")); count != 2 {
+		t.Fatalf("error: want 2 requests, but got %v", count)
+	}
+
+	c := m.InstrumentClient(fakeClient{})
+
+	_, _ = c.GetDetail(context.Background(), "TestSong", "TestGroup")
+	_, _ = c.GetDetail(context.Background(), "", "TestGroup")
+
+	if count := testutil.ToFloat64(m.clientErrors.WithLabelValues("GetDetail")); count != 1 {
+		t.Fatalf("error: want 1 client error, but got %v", count)
+	}
+
+	counts := 0
+
+	m.RegisterSongs(func(ctx context.Context) (int, error) {
+		counts++
+		return 42, nil
+	})
+
+	res := httptest.NewRecorder()
+	m.Handler().ServeHTTP(res, httptest.NewRequest("GET", "/metrics", nil))
+
+	for _, want := range []string{
+		`songs_http_requests_total{route="POST /songs",status="429"} 1`,
+		`songs_client_request_duration_seconds_count{operation="GetDetail"} 2`,
+		`songs_songs 42`,
+	} {
+		if !strings.Contains(res.Body.String(), want) {
+			t.Fatalf("error: metrics must contain %q", want)
+		}
+	}
+
+	// Count is cached between scrapes
+	m.Handler().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/metrics", nil))
+
+	if counts != 1 {
+		t.Fatalf("error: want songs counted once, but got %d", counts)
+	}
+}
+
+func TestSongsCollector(t *testing.T) {
+	now := time.Now()
+	counts := 0
+	fail := false
+
+	c := &songsCollector{
+		count: func(ctx context.Context) (int, error) {
+			if fail {
+				return 0, errors.New("connection refused")
+			}
+
+			counts++
+			return counts, nil
+		},
+		interval: time.Minute,
+		now:      func() time.Time { return now },
+		desc:     prometheus.NewDesc("songs", "", nil, nil),
+	}
+
+	collect := func() float64 {
+		return testutil.ToFloat64(c)
+	}
+
+	if got := collect(); got != 1 {
+		t.Fatalf("error: want the first count, but got %v", got)
+	}
+
+	now = now.Add(30 * time.Second)
+
+	if got := collect(); got != 1 || counts != 1 {
+		t.Fatalf("error: want cached count, but got %v after %d counts", got, counts)
+	}
+
+	now = now.Add(30 * time.Second)
+
+	if got := collect(); got != 2 {
+		t.Fatalf("error: want count refreshed after interval, but got %v", got)
+	}
+
+	// Error is reported and isn't cached
+	fail = true
+	now = now.Add(time.Minute)
+
+	ch := make(chan prometheus.Metric, 1)
+	c.Collect(ch)
+
+	if err := (<-ch).Write(nil); err == nil {
+		t.Fatal("error: want invalid metric with error of count")
+	}
+
+	fail = false
+
+	if got := collect(); got != 3 {
+		t.Fatalf("error: want count after error, but got %v", got)
+	}
+}
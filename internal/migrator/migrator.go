@@ -0,0 +1,162 @@
+package migrator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/s3nn1k/ef-mob-task/migrations"
+)
+
+// type Status represents state of database schema
+type Status struct {
+	// Version is the applied version, zero if no migrations are applied
+	Version uint
+	// Latest is the version of the last available migration
+	Latest uint
+	// Dirty means that the last migration failed and schema must be fixed manually
+	Dirty bool
+}
+
+// type Migrator applies migrations from source to database
+type Migrator struct {
+	m *migrate.Migrate
+	// openSource opens new instance of source to read available versions
+	openSource func() (source.Driver, error)
+}
+
+// New creates migrator for source and database connection string
+// Migrations embedded into binary are used if source url is empty, otherwise url is like file:///migrations
+func New(sourceURL string, connStr string) (*Migrator, error) {
+	openSource := func() (source.Driver, error) {
+		if sourceURL == "" {
+			return iofs.New(migrations.FS, ".")
+		}
+
+		return source.Open(sourceURL)
+	}
+
+	src, err := openSource()
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := migrate.NewWithSourceInstance("migrations", src, connStr)
+	if err != nil {
+		src.Close()
+		return nil, err
+	}
+
+	return &Migrator{m: m, openSource: openSource}, nil
+}
+
+// Up applies all not applied migrations
+func (m *Migrator) Up() error {
+	return ignoreNoChange(m.m.Up())
+}
+
+// Down rolls back the given count of migrations
+func (m *Migrator) Down(steps int) error {
+	if steps < 1 {
+		return fmt.Errorf("invalid count of steps %d, must be positive", steps)
+	}
+
+	return ignoreNoChange(m.m.Steps(-steps))
+}
+
+// Goto migrates up or down to the given version
+func (m *Migrator) Goto(version uint) error {
+	return ignoreNoChange(m.m.Migrate(version))
+}
+
+// Status returns applied and latest available versions
+func (m *Migrator) Status() (Status, error) {
+	var st Status
+
+	version, dirty, err := m.m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return Status{}, err
+	}
+
+	st.Version, st.Dirty = version, dirty
+
+	st.Latest, err = latest(m.openSource)
+	if err != nil {
+		return Status{}, err
+	}
+
+	return st, nil
+}
+
+// Check returns error if app can't work with schema of database
+// Schema must not be dirty or newer than the last migration of app, it must be up to date if required
+func (s Status) Check(upToDate bool) error {
+	switch {
+	case s.Dirty:
+		return fmt.Errorf("schema version %d is dirty, fix it manually and run migrate goto", s.Version)
+	case s.Version > s.Latest:
+		return fmt.Errorf("schema version %d is newer than the last migration %d of app, update app", s.Version, s.Latest)
+	case upToDate && s.Version < s.Latest:
+		return fmt.Errorf("schema version %d is older than the last migration %d of app, run migrate up", s.Version, s.Latest)
+	default:
+		return nil
+	}
+}
+
+// CheckServing returns error if running app can't serve requests with schema of database
+// Newer schema is allowed, it's applied by replicas of newer app during rolling update, old ones must keep serving
+func (s Status) CheckServing() error {
+	if !s.Dirty && s.Version > s.Latest {
+		return nil
+	}
+
+	return s.Check(true)
+}
+
+// Close closes connections to source and database
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.m.Close()
+
+	return errors.Join(srcErr, dbErr)
+}
+
+// latest returns version of the last migration of source
+func latest(openSource func() (source.Driver, error)) (uint, error) {
+	src, err := openSource()
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+
+		if err != nil {
+			return 0, err
+		}
+
+		version = next
+	}
+}
+
+// ignoreNoChange treats absence of migrations to apply as success
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+
+	return err
+}
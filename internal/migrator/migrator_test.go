@@ -0,0 +1,88 @@
+package migrator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/source"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/s3nn1k/ef-mob-task/migrations"
+)
+
+func TestLatest(t *testing.T) {
+	embedded := func() (source.Driver, error) {
+		return iofs.New(migrations.FS, ".")
+	}
+
+	files := func() (source.Driver, error) {
+		return source.Open("file://../../migrations")
+	}
+
+	for _, open := range []func() (source.Driver, error){embedded, files} {
+		version, err := latest(open)
+		if err != nil {
+			t.Fatalf("error not expected while reading migrations: %s", err)
+		}
+
+		if version != 5 {
+			t.Fatalf("error: want latest version 5, but got %d", version)
+		}
+	}
+}
+
+func TestStatusCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   Status
+		upToDate bool
+		wantErr  bool
+	}{
+		{name: "up to date", status: Status{Version: 5, Latest: 5}, upToDate: true},
+		{name: "older allowed", status: Status{Version: 4, Latest: 5}},
+		{name: "older required to be up to date", status: Status{Version: 4, Latest: 5}, upToDate: true, wantErr: true},
+		{name: "newer", status: Status{Version: 6, Latest: 5}, wantErr: true},
+		{name: "dirty", status: Status{Version: 5, Latest: 5, Dirty: true}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.status.Check(tt.upToDate); (err != nil) != tt.wantErr {
+				t.Fatalf("error: want error %t, but got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestStatusCheckServing(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  Status
+		wantErr bool
+	}{
+		{name: "up to date", status: Status{Version: 5, Latest: 5}},
+		{name: "newer", status: Status{Version: 6, Latest: 5}},
+		{name: "older", status: Status{Version: 4, Latest: 5}, wantErr: true},
+		{name: "dirty", status: Status{Version: 5, Latest: 5, Dirty: true}, wantErr: true},
+		{name: "newer dirty", status: Status{Version: 6, Latest: 5, Dirty: true}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.status.CheckServing(); (err != nil) != tt.wantErr {
+				t.Fatalf("error: want error %t, but got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestIgnoreNoChange(t *testing.T) {
+	if err := ignoreNoChange(migrate.ErrNoChange); err != nil {
+		t.Fatalf("error: absence of changes must not fail, but got %s", err)
+	}
+
+	if err := ignoreNoChange(errors.New("TestError")); err == nil {
+		t.Fatal("error: other errors must be returned")
+	}
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"log/slog"
+	"time"
+)
+
+// Available scopes of API keys
+// Every scope includes permissions of the previous ones
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+	ScopeAdmin = "admin"
+)
+
+// scopeLevels represents order of scopes
+var scopeLevels = map[string]int{
+	ScopeRead:  1,
+	ScopeWrite: 2,
+	ScopeAdmin: 3,
+}
+
+// type APIKey represents issued API key without it's secret
+type APIKey struct {
+	Id      int        `json:"id"`
+	Name    string     `json:"name"`
+	Prefix  string     `json:"prefix"`
+	Scope   string     `json:"scope"`
+	Created time.Time  `json:"createdAt"`
+	Revoked *time.Time `json:"revokedAt,omitempty"`
+}
+
+// IsScope checks that scope is known
+func IsScope(scope string) bool {
+	_, ok := scopeLevels[scope]
+	return ok
+}
+
+// ScopeAllows checks that scope includes the required one
+func ScopeAllows(have string, need string) bool {
+	level, ok := scopeLevels[have]
+	if !ok {
+		return false
+	}
+
+	return level >= scopeLevels[need]
+}
+
+// LogValue represents APIKey struct as slog.Value
+// Used for logging
+func (k *APIKey) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("id", k.Id),
+		slog.String("name", k.Name),
+		slog.String("prefix", k.Prefix),
+		slog.String("scope", k.Scope),
+	)
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"log/slog"
+
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+)
+
+// MaxBatchSize is the maximum count of songs that can be created in one batch
+const MaxBatchSize = 1000
+
+// type BatchResult represents result of creating one song from batch
+type BatchResult struct {
+	Index int    `json:"index"`
+	Song  *Song  `json:"song,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// type DeleteFilters represents filters that uses for delete many songs
+// At least one of filters must be set, so DeleteFilters can't match all songs
+type DeleteFilters struct {
+	Ids    []int  `json:"ids"`
+	Song   string `json:"song"`
+	Group  string `json:"group"`
+	Date   string `json:"date"`
+	DryRun bool   `json:"dryRun"`
+}
+
+// type DeleteResult represents ids of deleted songs or songs that will be deleted in dry run
+type DeleteResult struct {
+	Ids    []int `json:"ids"`
+	DryRun bool  `json:"dryRun"`
+}
+
+// IsEmpty checks that no one of filters is set
+func (d *DeleteFilters) IsEmpty() bool {
+	return len(d.Ids) == 0 && d.Song == "" && d.Group == "" && d.Date == ""
+}
+
+// LogValue represents BatchResult struct as slog.Value
+// Used for logging
+func (b *BatchResult) LogValue() slog.Value {
+	attrs := []slog.Attr{slog.Int("index", b.Index)}
+
+	if b.Song != nil {
+		attrs = append(attrs, slog.Any("song", b.Song.LogValue()))
+	}
+
+	if b.Error != "" {
+		attrs = append(attrs, slog.String("error", b.Error))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue represents DeleteFilters struct as slog.Value
+// Used for logging
+func (d *DeleteFilters) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Any("ids", d.Ids),
+		logger.String("song", d.Song),
+		logger.String("group", d.Group),
+		slog.String("date", d.Date),
+		slog.Bool("dryRun", d.DryRun),
+	)
+}
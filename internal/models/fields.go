@@ -0,0 +1,115 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Fields of song that could be selected by fields query parameter, they are the same as json names
+const (
+	FieldId          = "id"
+	FieldSong        = "song"
+	FieldGroup       = "group"
+	FieldText        = "text"
+	FieldLink        = "link"
+	FieldReleaseDate = "releaseDate"
+)
+
+// SongFields are all fields of song in order of json response
+var SongFields = []string{FieldId, FieldSong, FieldGroup, FieldText, FieldLink, FieldReleaseDate}
+
+// ErrUnknownField is returned if selected field is not one of SongFields
+var ErrUnknownField = errors.New("unknown field")
+
+// ParseFields parses comma separated list of song fields
+// Fields are returned without duplicates in order of SongFields, nil is returned for empty list
+func ParseFields(val string) ([]string, error) {
+	if strings.TrimSpace(val) == "" {
+		return nil, nil
+	}
+
+	selected := map[string]bool{}
+
+	for _, field := range strings.Split(val, ",") {
+		field = strings.TrimSpace(field)
+
+		if !slices.Contains(SongFields, field) {
+			return nil, fmt.Errorf("%w %q, must be one of %s", ErrUnknownField, field, strings.Join(SongFields, ", "))
+		}
+
+		selected[field] = true
+	}
+
+	fields := make([]string, 0, len(selected))
+	for _, field := range SongFields {
+		if selected[field] {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields, nil
+}
+
+// type PartialSong represents song that has only selected fields in json
+type PartialSong struct {
+	Song   Song
+	Fields []string
+}
+
+// PartialSongs returns songs with only the given fields
+func PartialSongs(songs []Song, fields []string) []PartialSong {
+	res := make([]PartialSong, 0, len(songs))
+	for _, song := range songs {
+		res = append(res, PartialSong{Song: song, Fields: fields})
+	}
+
+	return res
+}
+
+// MarshalJSON writes selected fields in order of SongFields
+func (p PartialSong) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	for i, field := range p.Fields {
+		var val any
+
+		switch field {
+		case FieldId:
+			val = p.Song.Id
+		case FieldSong:
+			val = p.Song.Song
+		case FieldGroup:
+			val = p.Song.Group
+		case FieldText:
+			val = p.Song.Text
+		case FieldLink:
+			val = p.Song.Link
+		case FieldReleaseDate:
+			val = p.Song.Date
+		default:
+			return nil, fmt.Errorf("%w %q", ErrUnknownField, field)
+		}
+
+		data, err := json.Marshal(val)
+		if err != nil {
+			return nil, err
+		}
+
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		fmt.Fprintf(&buf, "%q:", field)
+		buf.Write(data)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,28 @@
+package models
+
+import "log/slog"
+
+// Statuses of dependency checks
+const (
+	HealthOk    = "Ok"
+	HealthError = "Error"
+)
+
+// type HealthCheck represents result of dependency check
+type HealthCheck struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration int64  `json:"durationMs"`
+}
+
+// LogValue represents HealthCheck struct as slog.Value
+// Used for logging
+func (h *HealthCheck) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("name", h.Name),
+		slog.String("status", h.Status),
+		slog.String("error", h.Error),
+		slog.Int64("durationMs", h.Duration),
+	)
+}
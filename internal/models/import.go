@@ -0,0 +1,52 @@
+package models
+
+import "log/slog"
+
+// Available formats of import and export files
+const (
+	FormatCsv   = "csv"
+	FormatJsonl = "jsonl"
+)
+
+// DateLayout is the format of song release date
+const DateLayout = "02.01.2006"
+
+// type ImportOptions represents options of songs import
+type ImportOptions struct {
+	Format string
+	Enrich bool
+}
+
+// type RowError represents error of one row of imported file
+// Rows are numbered from 1, header row of csv file is not counted
+type RowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// type ImportReport represents result of songs import
+type ImportReport struct {
+	Total   int        `json:"total"`
+	Created int        `json:"created"`
+	Failed  int        `json:"failed"`
+	Errors  []RowError `json:"errors"`
+}
+
+// LogValue represents ImportOptions struct as slog.Value
+// Used for logging
+func (i *ImportOptions) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("format", i.Format),
+		slog.Bool("enrich", i.Enrich),
+	)
+}
+
+// LogValue represents ImportReport struct as slog.Value
+// Used for logging
+func (i *ImportReport) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("total", i.Total),
+		slog.Int("created", i.Created),
+		slog.Int("failed", i.Failed),
+	)
+}
@@ -0,0 +1,6 @@
+package models
+
+// type LogLevel represents logging level that could be changed at runtime
+type LogLevel struct {
+	Level string `json:"level" example:"info"`
+}
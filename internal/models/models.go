@@ -4,6 +4,9 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
+
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
 )
 
 // type Song represents song info
@@ -16,14 +19,58 @@ type Song struct {
 	Date  string `json:"releaseDate"`
 }
 
+// Types of song sections
+const (
+	SectionVerse     = "verse"
+	SectionChorus    = "chorus"
+	SectionPreChorus = "pre-chorus"
+	SectionBridge    = "bridge"
+	SectionIntro     = "intro"
+	SectionOutro     = "outro"
+	SectionHook      = "hook"
+)
+
+// type Section represents typed part of song text
+type Section struct {
+	Type  string `json:"type"`
+	Label string `json:"label"`
+	Text  string `json:"text"`
+}
+
+// type LyricLine represents line of synchronized lyrics with it's start time in milliseconds
+type LyricLine struct {
+	Time int64  `json:"timeMs"`
+	Text string `json:"text"`
+}
+
+// type Lyrics represents synchronized lyrics of song
+type Lyrics struct {
+	Id    int         `json:"id"`
+	Song  string      `json:"song"`
+	Group string      `json:"group"`
+	Lines []LyricLine `json:"lines"`
+}
+
+// type Verse represents song verse with it's time range in synchronized lyrics
+// Time range is zero if song has no synchronized lyrics for the verse
+type Verse struct {
+	Text  string `json:"text"`
+	Start int64  `json:"startMs"`
+	End   int64  `json:"endMs"`
+}
+
 // type AllFilters represents filters that uses for get library of songs
 type GetFilters struct {
 	Limit  int
 	Offset int
 	Id     int
-	Song   string
-	Group  string
-	Date   string
+	// Ids selects many songs at once, used for batch loading
+	Ids   []int
+	Song  string
+	Group string
+	Date  string
+	// Fields are selected fields of songs, all fields are selected if it's empty
+	Fields []string
 }
 
 // type SongFilters represents filters that uses for get song text with verse
@@ -31,6 +78,10 @@ type GetVersesFilters struct {
 	Id     int
 	Limit  int
 	Offset int
+	Type   string
+	Timed  bool
+	Lang   string
+	Accept string
 }
 
 // SetQueryId set's id from request url query to Song struct
@@ -92,6 +143,13 @@ func (g *GetFilters) SetQueryData(r *http.Request) error {
 	g.Group = r.URL.Query().Get("group")
 	g.Date = r.URL.Query().Get("date")
 
+	fields, err := ParseFields(r.URL.Query().Get("fields"))
+	if err != nil {
+		return err
+	}
+
+	g.Fields = fields
+
 	return nil
 }
 
@@ -140,41 +198,62 @@ func (g *GetVersesFilters) SetQueryData(r *http.Request) error {
 		g.Offset = 0
 	}
 
+	g.Type = strings.ToLower(r.URL.Query().Get("type"))
+	g.Timed = r.URL.Query().Get("timed") == "true"
+	g.Lang = r.URL.Query().Get("lang")
+	g.Accept = r.Header.Get("Accept-Language")
+
 	return nil
 }
 
-// AsLogValue represents Song struct as slog.Value
-// Used for logging
-func (s *Song) AsLogValue() slog.Value {
+// IsSectionType checks that the given string is one of available section types
+func IsSectionType(t string) bool {
+	switch t {
+	case SectionVerse, SectionChorus, SectionPreChorus, SectionBridge, SectionIntro, SectionOutro, SectionHook:
+		return true
+	default:
+		return false
+	}
+}
+
+// LogValue represents Song struct as slog.Value
+// Text is truncated or redacted by logging policy
+func (s *Song) LogValue() slog.Value {
 	return slog.GroupValue(
 		slog.Int("id", s.Id),
-		slog.String("song", s.Song),
-		slog.String("group", s.Group),
-		slog.String("text", s.Text),
-		slog.String("link", s.Link),
+		logger.String("song", s.Song),
+		logger.String("group", s.Group),
+		logger.Text("text", s.Text),
+		logger.String("link", s.Link),
 		slog.String("date", s.Date),
 	)
 }
 
-// AsLogValue represents AllFilters struct as slog.Value
+// LogValue represents AllFilters struct as slog.Value
 // Used for logging
-func (g *GetFilters) AsLogValue() slog.Value {
+func (g *GetFilters) LogValue() slog.Value {
 	return slog.GroupValue(
 		slog.Int("limit", g.Limit),
 		slog.Int("offset", g.Offset),
 		slog.Int("id", g.Id),
-		slog.String("song", g.Song),
-		slog.String("group", g.Group),
+		slog.Any("ids", g.Ids),
+		logger.String("song", g.Song),
+		logger.String("group", g.Group),
 		slog.String("date", g.Date),
+		slog.Any("fields", g.Fields),
 	)
 }
 
-// AsLogValue represents SongFilters struct as slog.Value
+// LogValue represents SongFilters struct as slog.Value
 // Used for logging
-func (g *GetVersesFilters) AsLogValue() slog.Value {
+func (g *GetVersesFilters) LogValue() slog.Value {
 	return slog.GroupValue(
 		slog.Int("id", g.Id),
 		slog.Int("limit", g.Limit),
 		slog.Int("offset", g.Offset),
+		slog.String("type", g.Type),
+		slog.Bool("timed", g.Timed),
+		slog.String("lang", g.Lang),
+		slog.String("accept", g.Accept),
 	)
 }
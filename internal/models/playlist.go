@@ -0,0 +1,204 @@
+package models
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+)
+
+// type Playlist represents ordered collection of songs
+type Playlist struct {
+	Id    int            `json:"id"`
+	Name  string         `json:"name"`
+	Songs []PlaylistSong `json:"songs,omitempty"`
+}
+
+// type PlaylistSong represents song with it's position in playlist
+type PlaylistSong struct {
+	Position int `json:"position"`
+	Song
+}
+
+// type PlaylistItem represents song that will be placed into playlist
+// Position starts from 1, zero position means the end of playlist
+type PlaylistItem struct {
+	PlaylistId int `json:"-"`
+	SongId     int `json:"songId"`
+	Position   int `json:"position"`
+}
+
+// type PlaylistMove represents moving of song inside playlist from one position to another
+type PlaylistMove struct {
+	PlaylistId int `json:"-"`
+	From       int `json:"-"`
+	To         int `json:"position"`
+}
+
+// type GetPlaylistFilters represents filters that uses for get playlist with songs
+type GetPlaylistFilters struct {
+	Id     int
+	Limit  int
+	Offset int
+}
+
+// SetQueryId set's id from request url query to Playlist struct
+func (p *Playlist) SetQueryId(r *http.Request) error {
+	val := r.PathValue("id")
+	if val != "" {
+		id, err := strconv.Atoi(val)
+		if err != nil {
+			return err
+		}
+
+		p.Id = id
+	}
+
+	return nil
+}
+
+// SetQueryData set's playlist id and position from request url query to PlaylistItem struct
+func (p *PlaylistItem) SetQueryData(r *http.Request) error {
+	val := r.PathValue("id")
+	if val != "" {
+		id, err := strconv.Atoi(val)
+		if err != nil {
+			return err
+		}
+
+		p.PlaylistId = id
+	}
+
+	val = r.PathValue("position")
+	if val != "" {
+		position, err := strconv.Atoi(val)
+		if err != nil {
+			return err
+		}
+
+		p.Position = position
+	}
+
+	return nil
+}
+
+// SetQueryData set's playlist id and position from request url query to PlaylistMove struct
+func (p *PlaylistMove) SetQueryData(r *http.Request) error {
+	val := r.PathValue("id")
+	if val != "" {
+		id, err := strconv.Atoi(val)
+		if err != nil {
+			return err
+		}
+
+		p.PlaylistId = id
+	}
+
+	val = r.PathValue("position")
+	if val != "" {
+		position, err := strconv.Atoi(val)
+		if err != nil {
+			return err
+		}
+
+		p.From = position
+	}
+
+	return nil
+}
+
+// SetQueryId set's id from request url query to GetPlaylistFilters struct
+func (g *GetPlaylistFilters) SetQueryId(r *http.Request) error {
+	val := r.PathValue("id")
+	if val != "" {
+		id, err := strconv.Atoi(val)
+		if err != nil {
+			return err
+		}
+
+		g.Id = id
+	}
+
+	return nil
+}
+
+// SetQueryData set's data from request url query to GetPlaylistFilters struct
+func (g *GetPlaylistFilters) SetQueryData(r *http.Request) error {
+	val := r.URL.Query().Get("limit")
+	if val != "" {
+		limit, err := strconv.Atoi(val)
+		if err != nil {
+			return err
+		}
+
+		g.Limit = limit
+	}
+
+	if g.Limit < 1 {
+		g.Limit = 10
+	}
+
+	val = r.URL.Query().Get("offset")
+	if val != "" {
+		offset, err := strconv.Atoi(val)
+		if err != nil {
+			return err
+		}
+
+		g.Offset = offset
+	}
+
+	if g.Offset < 1 {
+		g.Offset = 0
+	}
+
+	return nil
+}
+
+// LogValue represents Playlist struct as slog.Value
+// Used for logging
+func (p *Playlist) LogValue() slog.Value {
+	songs := logger.ListFunc(p.Songs, func(song PlaylistSong) slog.Value {
+		return slog.GroupValue(
+			slog.Int("position", song.Position),
+			slog.Any("song", song.LogValue()),
+		)
+	}).LogValue()
+
+	return slog.GroupValue(
+		slog.Int("id", p.Id),
+		slog.String("name", p.Name),
+		slog.Any("songs", songs),
+	)
+}
+
+// LogValue represents PlaylistItem struct as slog.Value
+// Used for logging
+func (p *PlaylistItem) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("playlistId", p.PlaylistId),
+		slog.Int("songId", p.SongId),
+		slog.Int("position", p.Position),
+	)
+}
+
+// LogValue represents PlaylistMove struct as slog.Value
+// Used for logging
+func (p *PlaylistMove) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("playlistId", p.PlaylistId),
+		slog.Int("from", p.From),
+		slog.Int("to", p.To),
+	)
+}
+
+// LogValue represents GetPlaylistFilters struct as slog.Value
+// Used for logging
+func (g *GetPlaylistFilters) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("id", g.Id),
+		slog.Int("limit", g.Limit),
+		slog.Int("offset", g.Offset),
+	)
+}
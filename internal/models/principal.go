@@ -0,0 +1,37 @@
+package models
+
+import (
+	"log/slog"
+	"slices"
+)
+
+// Available kinds of principals
+const (
+	PrincipalKey   = "key"
+	PrincipalToken = "token"
+)
+
+// type Principal represents authenticated client of request
+// Roles are the same as API key scopes, so every role includes permissions of the previous ones
+type Principal struct {
+	Kind    string
+	Subject string
+	Roles   []string
+}
+
+// HasRole checks that any of principal's roles includes the required one
+func (p *Principal) HasRole(role string) bool {
+	return slices.ContainsFunc(p.Roles, func(have string) bool {
+		return ScopeAllows(have, role)
+	})
+}
+
+// LogValue represents Principal struct as slog.Value
+// Used for logging
+func (p *Principal) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("kind", p.Kind),
+		slog.String("subject", p.Subject),
+		slog.Any("roles", p.Roles),
+	)
+}
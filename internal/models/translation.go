@@ -0,0 +1,63 @@
+package models
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+	"golang.org/x/text/language"
+)
+
+// type Translation represents translated text of song in the given language
+type Translation struct {
+	Id   int    `json:"id"`
+	Lang string `json:"lang"`
+	Text string `json:"text"`
+}
+
+// SetQueryData set's song id and language from request url query to Translation struct
+// Language is converted into canonical BCP 47 form
+func (t *Translation) SetQueryData(r *http.Request) error {
+	val := r.PathValue("id")
+	if val != "" {
+		id, err := strconv.Atoi(val)
+		if err != nil {
+			return err
+		}
+
+		t.Id = id
+	}
+
+	val = r.PathValue("lang")
+	if val != "" {
+		lang, err := ParseLang(val)
+		if err != nil {
+			return err
+		}
+
+		t.Lang = lang
+	}
+
+	return nil
+}
+
+// ParseLang validates BCP 47 language tag and returns it in canonical form
+func ParseLang(lang string) (string, error) {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return "", err
+	}
+
+	return tag.String(), nil
+}
+
+// LogValue represents Translation struct as slog.Value
+// Text is truncated or redacted by logging policy
+func (t *Translation) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("id", t.Id),
+		slog.String("lang", t.Lang),
+		logger.Text("text", t.Text),
+	)
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/internal/storage"
+)
+
+const (
+	// keyPrefix is the prefix of every issued API key, helps to recognize leaked keys
+	keyPrefix = "sk_"
+	// keyBytes is the count of random bytes in API key
+	keyBytes = 32
+	// keyVisible is the count of key characters that are stored as is to identify it
+	keyVisible = len(keyPrefix) + 8
+)
+
+// go run github.com/vektra/mockery/v2@v2.45.0 --name=KeyServiceIface
+type KeyServiceIface interface {
+	Issue(ctx context.Context, name string, scope string) (models.APIKey, string, error)
+	Authenticate(ctx context.Context, secret string) (models.APIKey, bool, error)
+	List(ctx context.Context) ([]models.APIKey, error)
+	Revoke(ctx context.Context, id int) (bool, error)
+}
+
+type KeyService struct {
+	storage storage.KeyStorage
+}
+
+func NewKeyService(s storage.KeyStorage) KeyServiceIface {
+	return &KeyService{
+		storage: s,
+	}
+}
+
+// Issue creates new API key with the given scope
+// Returns secret of key, it's shown only once because only it's hash is stored
+func (s *KeyService) Issue(ctx context.Context, name string, scope string) (models.APIKey, string, error) {
+	if !models.IsScope(scope) {
+		return models.APIKey{}, "", fmt.Errorf("unknown scope: %s", scope)
+	}
+
+	buf := make([]byte, keyBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return models.APIKey{}, "", fmt.Errorf("can't generate api key: %w", err)
+	}
+
+	secret := keyPrefix + base64.RawURLEncoding.EncodeToString(buf)
+
+	key, err := s.storage.Create(ctx, models.APIKey{Name: name, Prefix: secret[:keyVisible], Scope: scope}, hashKey(secret))
+	if err != nil {
+		return models.APIKey{}, "", err
+	}
+
+	return key, secret, nil
+}
+
+// Authenticate returns API key by it's secret
+// Returns false if key not exists or revoked
+func (s *KeyService) Authenticate(ctx context.Context, secret string) (models.APIKey, bool, error) {
+	if !strings.HasPrefix(secret, keyPrefix) {
+		return models.APIKey{}, false, nil
+	}
+
+	return s.storage.GetByHash(ctx, hashKey(secret))
+}
+
+func (s *KeyService) List(ctx context.Context) ([]models.APIKey, error) {
+	return s.storage.List(ctx)
+}
+
+func (s *KeyService) Revoke(ctx context.Context, id int) (bool, error) {
+	return s.storage.Revoke(ctx, id)
+}
+
+// hashKey returns hex encoded sha256 hash of key
+// Keys have enough entropy, so they don't need salt and slow hash
+func hashKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+)
+
+// exportFlushSize is the count of csv rows after which they are flushed into writer
+const exportFlushSize = 100
+
+// csvHeader is the header of exported csv file, it's compatible with import
+var csvHeader = models.SongFields
+
+// Export writes all songs that match filters into w in csv or jsonl format
+// Only selected fields of songs are written if fields of filters are set.
+// Nothing is written into w if export fails before the first song
+func (s *Service) Export(ctx context.Context, w io.Writer, format string, filters models.GetFilters) error {
+	ctx, span := tracer.Start(ctx, "Service.Export")
+	defer span.End()
+
+	logger.LogUse(ctx).Debug("Service.Export", "input", filters.LogValue(), "format", format)
+
+	var write func(models.Song) error
+	var flush func() error
+
+	switch format {
+	case models.FormatCsv:
+		writer := csv.NewWriter(w)
+		count := 0
+
+		header := csvHeader
+		if len(filters.Fields) > 0 {
+			header = filters.Fields
+		}
+
+		// Header is buffered until the first flush
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+
+		write = func(song models.Song) error {
+			if err := writer.Write(songRecord(song, header)); err != nil {
+				return err
+			}
+
+			if count++; count%exportFlushSize == 0 {
+				writer.Flush()
+
+				return writer.Error()
+			}
+
+			return nil
+		}
+
+		flush = func() error {
+			writer.Flush()
+
+			return writer.Error()
+		}
+	case models.FormatJsonl:
+		encoder := json.NewEncoder(w)
+
+		write = func(song models.Song) error {
+			if len(filters.Fields) > 0 {
+				return encoder.Encode(models.PartialSong{Song: song, Fields: filters.Fields})
+			}
+
+			return encoder.Encode(song)
+		}
+
+		flush = func() error {
+			return nil
+		}
+	default:
+		return fmt.Errorf("unknown export format: %s", format)
+	}
+
+	if err := s.storage.Export(ctx, filters, write); err != nil {
+		return err
+	}
+
+	return flush()
+}
+
+// Stream calls fn for every song that match filters in order of id
+// Songs are never loaded into memory at once, limit and offset of filters are ignored
+func (s *Service) Stream(ctx context.Context, filters models.GetFilters, fn func(models.Song) error) error {
+	ctx, span := tracer.Start(ctx, "Service.Stream")
+	defer span.End()
+
+	logger.LogUse(ctx).Debug("Service.Stream", "input", filters.LogValue())
+
+	return s.storage.Export(ctx, filters, fn)
+}
+
+// songRecord returns the given fields of song in order of csv header
+func songRecord(song models.Song, header []string) []string {
+	record := make([]string, 0, len(header))
+
+	for _, field := range header {
+		switch field {
+		case models.FieldId:
+			record = append(record, strconv.Itoa(song.Id))
+		case models.FieldSong:
+			record = append(record, song.Song)
+		case models.FieldGroup:
+			record = append(record, song.Group)
+		case models.FieldText:
+			record = append(record, song.Text)
+		case models.FieldLink:
+			record = append(record, song.Link)
+		case models.FieldReleaseDate:
+			record = append(record, song.Date)
+		}
+	}
+
+	return record
+}
@@ -0,0 +1,333 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+)
+
+const (
+	// importBatchSize is the count of songs that are written into storage at once while importing
+	importBatchSize = 100
+	// maxFieldLength is the maximum length of song fields except text in storage
+	maxFieldLength = 255
+	// maxLineLength is the maximum length of one line of jsonl file
+	maxLineLength = 1 << 20
+)
+
+// ErrInvalidFile is returned by Import if file can't be read in the given format
+var ErrInvalidFile = errors.New("invalid file")
+
+// type songReader reads songs from imported file one by one
+// Returns number of the read row, io.EOF when there are no more rows
+type songReader interface {
+	Read() (models.Song, int, error)
+}
+
+// type importRow represents valid song with number of it's row
+type importRow struct {
+	n    int
+	song models.Song
+}
+
+// Import reads songs from file in csv or jsonl format, validates them and writes into storage in batches
+// Missing text, link and release date are taken from API if enrich option is set.
+// Invalid rows don't stop the import, their errors are returned in report
+func (s *Service) Import(ctx context.Context, r io.Reader, opts models.ImportOptions) (models.ImportReport, error) {
+	ctx, span := tracer.Start(ctx, "Service.Import")
+	defer span.End()
+
+	logger.LogUse(ctx).Debug("Service.Import", "options", opts.LogValue())
+
+	report := models.ImportReport{Errors: []models.RowError{}}
+
+	reader, err := newSongReader(r, opts.Format)
+	if err != nil {
+		return report, err
+	}
+
+	batch := make([]importRow, 0, importBatchSize)
+
+	fail := func(n int, msg string) {
+		report.Failed++
+		report.Errors = append(report.Errors, models.RowError{Row: n, Error: msg})
+	}
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		songs := make([]models.Song, 0, len(batch))
+		for _, row := range batch {
+			songs = append(songs, row.song)
+		}
+
+		if _, err := s.storage.CreateBatch(ctx, songs); err != nil {
+			logger.LogUse(ctx).Error(err.Error(), slog.Int("from", batch[0].n), slog.Int("to", batch[len(batch)-1].n))
+
+			for _, row := range batch {
+				fail(row.n, "Can't write song into storage")
+			}
+		} else {
+			report.Created += len(batch)
+		}
+
+		batch = batch[:0]
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		song, n, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		report.Total++
+
+		if err != nil {
+			fail(n, err.Error())
+			continue
+		}
+
+		if opts.Enrich {
+			song, err = s.enrich(ctx, song)
+			if err != nil {
+				logger.LogUse(ctx).Error(err.Error(), "input", song.LogValue())
+
+				fail(n, "Can't get song details")
+				continue
+			}
+		}
+
+		if err := validateSong(song); err != nil {
+			fail(n, err.Error())
+			continue
+		}
+
+		batch = append(batch, importRow{n: n, song: song})
+
+		if len(batch) == importBatchSize {
+			flush()
+		}
+	}
+
+	flush()
+
+	logger.LogUse(ctx).Debug("Result", "report", report.LogValue())
+
+	return report, nil
+}
+
+// enrich fills missing text, link and release date of song with details from API
+func (s *Service) enrich(ctx context.Context, song models.Song) (models.Song, error) {
+	if song.Text != "" && song.Link != "" && song.Date != "" {
+		return song, nil
+	}
+
+	detail, err := s.client.GetDetail(ctx, song.Song, song.Group)
+	if err != nil {
+		return song, err
+	}
+
+	if song.Text == "" {
+		song.Text = detail.Text
+	}
+
+	if song.Link == "" {
+		song.Link = detail.Link
+	}
+
+	if song.Date == "" {
+		song.Date = detail.Date
+	}
+
+	return song, nil
+}
+
+// validateSong checks that song can be written into storage
+func validateSong(song models.Song) error {
+	if strings.TrimSpace(song.Song) == "" || strings.TrimSpace(song.Group) == "" {
+		return fmt.Errorf("song and group must not be empty")
+	}
+
+	fields := []struct {
+		name string
+		val  string
+	}{
+		{"song", song.Song},
+		{"group", song.Group},
+		{"link", song.Link},
+		{"releaseDate", song.Date},
+	}
+
+	for _, field := range fields {
+		if len(field.val) > maxFieldLength {
+			return fmt.Errorf("%s must not be longer than %d bytes", field.name, maxFieldLength)
+		}
+	}
+
+	if song.Date != "" {
+		if _, err := time.Parse(models.DateLayout, song.Date); err != nil {
+			return fmt.Errorf("releaseDate must be in format %s", models.DateLayout)
+		}
+	}
+
+	return nil
+}
+
+// newSongReader creates reader for the given format
+func newSongReader(r io.Reader, format string) (songReader, error) {
+	switch format {
+	case models.FormatCsv:
+		return newCsvReader(r)
+	case models.FormatJsonl:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLineLength)
+
+		return &jsonlReader{scanner: scanner}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown import format: %s", ErrInvalidFile, format)
+	}
+}
+
+// type csvReader reads songs from csv file with header
+// Columns are matched by header names, unknown columns are ignored
+type csvReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+	n       int
+	failed  bool
+}
+
+func newCsvReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't read csv header: %w", ErrInvalidFile, err)
+	}
+
+	columns := make(map[string]int)
+
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))) {
+		case "song":
+			columns["song"] = i
+		case "group", "group_name":
+			columns["group"] = i
+		case "text":
+			columns["text"] = i
+		case "link":
+			columns["link"] = i
+		case "releasedate", "release_date", "date":
+			columns["date"] = i
+		}
+	}
+
+	if _, ok := columns["song"]; !ok {
+		return nil, fmt.Errorf("%w: csv header must contain song and group columns", ErrInvalidFile)
+	}
+
+	if _, ok := columns["group"]; !ok {
+		return nil, fmt.Errorf("%w: csv header must contain song and group columns", ErrInvalidFile)
+	}
+
+	return &csvReader{reader: reader, columns: columns}, nil
+}
+
+func (c *csvReader) Read() (models.Song, int, error) {
+	if c.failed {
+		return models.Song{}, 0, io.EOF
+	}
+
+	record, err := c.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return models.Song{}, 0, io.EOF
+	}
+
+	c.n++
+
+	if err != nil {
+		var parseErr *csv.ParseError
+		if !errors.As(err, &parseErr) {
+			c.failed = true
+
+			// Reader returns the same error forever after failed read, so the rest of file is skipped
+			return models.Song{}, c.n, fmt.Errorf("can't read row: %w", err)
+		}
+
+		return models.Song{}, c.n, fmt.Errorf("invalid csv row: %w", err)
+	}
+
+	field := func(name string) string {
+		i, ok := c.columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+
+		return strings.TrimSpace(record[i])
+	}
+
+	return models.Song{
+		Song:  field("song"),
+		Group: field("group"),
+		Text:  field("text"),
+		Link:  field("link"),
+		Date:  field("date"),
+	}, c.n, nil
+}
+
+// type jsonlReader reads songs from file with json object on every line
+// Empty lines are skipped, rows are numbered by lines
+type jsonlReader struct {
+	scanner *bufio.Scanner
+	n       int
+	failed  bool
+}
+
+func (j *jsonlReader) Read() (models.Song, int, error) {
+	for j.scanner.Scan() {
+		j.n++
+
+		line := strings.TrimSpace(j.scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var song models.Song
+
+		if err := json.Unmarshal([]byte(line), &song); err != nil {
+			return models.Song{}, j.n, fmt.Errorf("invalid json: %w", err)
+		}
+
+		song.Id = 0
+
+		return song, j.n, nil
+	}
+
+	if err := j.scanner.Err(); err != nil && !j.failed {
+		j.n++
+		j.failed = true
+
+		// Scanner can't continue after error, so the rest of file is skipped
+		return models.Song{}, j.n, fmt.Errorf("can't read line: %w", err)
+	}
+
+	return models.Song{}, 0, io.EOF
+}
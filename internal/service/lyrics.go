@@ -0,0 +1,222 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+)
+
+// sectionMarker matches section labels like [Chorus] or [Verse 2]
+var sectionMarker = regexp.MustCompile(`^\[\s*([^\]]*[^\]\s])\s*\]$`)
+
+// type stanza represents block of lines with optional label before grouping into sections
+type stanza struct {
+	label string
+	lines []string
+}
+
+// parseSections splits song text into typed sections
+// Stanzas are separated by empty lines, section markers like [Chorus] set type and label of the next stanza.
+// Marker without lines repeats the text of previous section with the same label.
+// Unlabeled stanzas that occur in the text more than once are treated as chorus
+func parseSections(text string) []models.Section {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	var stanzas []stanza
+	var current stanza
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+
+		if line == "" {
+			// Marker followed by empty line is applied to the next stanza
+			if len(current.lines) > 0 {
+				stanzas = append(stanzas, current)
+				current = stanza{}
+			}
+
+			continue
+		}
+
+		if m := sectionMarker.FindStringSubmatch(line); m != nil {
+			if len(current.lines) > 0 || current.label != "" {
+				stanzas = append(stanzas, current)
+			}
+
+			current = stanza{label: m[1]}
+
+			continue
+		}
+
+		current.lines = append(current.lines, line)
+	}
+
+	if len(current.lines) > 0 || current.label != "" {
+		stanzas = append(stanzas, current)
+	}
+
+	counts := make(map[string]int)
+	for _, st := range stanzas {
+		if len(st.lines) > 0 {
+			counts[strings.Join(st.lines, "\n")]++
+		}
+	}
+
+	labeled := make(map[string]string)
+	sections := make([]models.Section, 0, len(stanzas))
+	verses := 0
+
+	for _, st := range stanzas {
+		section := models.Section{
+			Label: st.label,
+			Text:  strings.Join(st.lines, "\n"),
+		}
+
+		if st.label != "" {
+			section.Type = sectionType(st.label)
+
+			key := strings.ToLower(st.label)
+
+			if section.Text == "" {
+				section.Text = labeled[key]
+			} else {
+				labeled[key] = section.Text
+			}
+
+			if section.Text == "" {
+				continue
+			}
+		} else if counts[section.Text] > 1 {
+			section.Type = models.SectionChorus
+			section.Label = "Chorus"
+		} else {
+			section.Type = models.SectionVerse
+		}
+
+		if section.Type == models.SectionVerse {
+			verses++
+
+			if section.Label == "" {
+				section.Label = fmt.Sprintf("Verse %d", verses)
+			}
+		}
+
+		sections = append(sections, section)
+	}
+
+	return sections
+}
+
+// sectionType returns type of section by it's label
+// Unknown labels are treated as verses
+func sectionType(label string) string {
+	// Labels could contain performer after colon, e.g. [Verse 2: Artist]
+	if i := strings.Index(label, ":"); i != -1 {
+		label = label[:i]
+	}
+
+	label = strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return -1
+		}
+
+		if unicode.IsSpace(r) || r == '_' {
+			return '-'
+		}
+
+		return unicode.ToLower(r)
+	}, label)
+
+	switch strings.Trim(label, "-") {
+	case "chorus", "refrain":
+		return models.SectionChorus
+	case "pre-chorus", "prechorus":
+		return models.SectionPreChorus
+	case "bridge":
+		return models.SectionBridge
+	case "intro":
+		return models.SectionIntro
+	case "outro":
+		return models.SectionOutro
+	case "hook":
+		return models.SectionHook
+	default:
+		return models.SectionVerse
+	}
+}
+
+// filterSections returns sections with the given type
+// Empty type means all sections
+func filterSections(sections []models.Section, kind string) []models.Section {
+	if kind == "" {
+		return sections
+	}
+
+	res := make([]models.Section, 0, len(sections))
+
+	for _, section := range sections {
+		if section.Type == kind {
+			res = append(res, section)
+		}
+	}
+
+	return res
+}
+
+// timeVerses matches synchronized lyrics lines with sections lines sequentially and returns verses with time ranges
+// Lyrics lines with empty text are treated as pauses. Verse ends when the next lyrics line starts
+func timeVerses(sections []models.Section, lines []models.LyricLine) []models.Verse {
+	verses := make([]models.Verse, 0, len(sections))
+
+	idx := 0
+
+	for _, section := range sections {
+		verse := models.Verse{Text: section.Text}
+
+		first, last := -1, -1
+
+		for n := strings.Count(section.Text, "\n") + 1; n > 0 && idx < len(lines); idx++ {
+			if lines[idx].Text == "" {
+				continue
+			}
+
+			if first < 0 {
+				first = idx
+			}
+
+			last = idx
+			n--
+		}
+
+		if first >= 0 {
+			verse.Start = lines[first].Time
+			verse.End = lines[last].Time
+
+			if last+1 < len(lines) {
+				verse.End = lines[last+1].Time
+			}
+		}
+
+		verses = append(verses, verse)
+	}
+
+	return verses
+}
+
+// paginate returns part of items using limit and offset
+func paginate[T any](items []T, limit int, offset int) []T {
+	if len(items) <= offset {
+		return []T{}
+	}
+
+	items = items[offset:]
+	if len(items) > limit {
+		items = items[:limit]
+	}
+
+	return items
+}
@@ -0,0 +1,204 @@
+// Code generated by mockery v2.45.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/s3nn1k/ef-mob-task/internal/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PlaylistServiceIface is an autogenerated mock type for the PlaylistServiceIface type
+type PlaylistServiceIface struct {
+	mock.Mock
+}
+
+// AddSong provides a mock function with given fields: ctx, item
+func (_m *PlaylistServiceIface) AddSong(ctx context.Context, item models.PlaylistItem) (bool, error) {
+	ret := _m.Called(ctx, item)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddSong")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.PlaylistItem) (bool, error)); ok {
+		return rf(ctx, item)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.PlaylistItem) bool); ok {
+		r0 = rf(ctx, item)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.PlaylistItem) error); ok {
+		r1 = rf(ctx, item)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, name
+func (_m *PlaylistServiceIface) Create(ctx context.Context, name string) (models.Playlist, error) {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 models.Playlist
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (models.Playlist, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.Playlist); ok {
+		r0 = rf(ctx, name)
+	} else {
+		r0 = ret.Get(0).(models.Playlist)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *PlaylistServiceIface) Delete(ctx context.Context, id int) (bool, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (bool, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) bool); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Get provides a mock function with given fields: ctx, filters
+func (_m *PlaylistServiceIface) Get(ctx context.Context, filters models.GetPlaylistFilters) (models.Playlist, bool, error) {
+	ret := _m.Called(ctx, filters)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 models.Playlist
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.GetPlaylistFilters) (models.Playlist, bool, error)); ok {
+		return rf(ctx, filters)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.GetPlaylistFilters) models.Playlist); ok {
+		r0 = rf(ctx, filters)
+	} else {
+		r0 = ret.Get(0).(models.Playlist)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.GetPlaylistFilters) bool); ok {
+		r1 = rf(ctx, filters)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, models.GetPlaylistFilters) error); ok {
+		r2 = rf(ctx, filters)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MoveSong provides a mock function with given fields: ctx, move
+func (_m *PlaylistServiceIface) MoveSong(ctx context.Context, move models.PlaylistMove) (bool, error) {
+	ret := _m.Called(ctx, move)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MoveSong")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.PlaylistMove) (bool, error)); ok {
+		return rf(ctx, move)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.PlaylistMove) bool); ok {
+		r0 = rf(ctx, move)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.PlaylistMove) error); ok {
+		r1 = rf(ctx, move)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveSong provides a mock function with given fields: ctx, item
+func (_m *PlaylistServiceIface) RemoveSong(ctx context.Context, item models.PlaylistItem) (bool, error) {
+	ret := _m.Called(ctx, item)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveSong")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.PlaylistItem) (bool, error)); ok {
+		return rf(ctx, item)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.PlaylistItem) bool); ok {
+		r0 = rf(ctx, item)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.PlaylistItem) error); ok {
+		r1 = rf(ctx, item)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPlaylistServiceIface creates a new instance of PlaylistServiceIface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPlaylistServiceIface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PlaylistServiceIface {
+	mock := &PlaylistServiceIface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -4,9 +4,11 @@ package mocks
 
 import (
 	context "context"
+	io "io"
 
-	models "github.com/s3nn1k/ef-mob-task/internal/models"
 	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/s3nn1k/ef-mob-task/internal/models"
 )
 
 // ServiceIface is an autogenerated mock type for the ServiceIface type
@@ -42,6 +44,36 @@ func (_m *ServiceIface) Create(ctx context.Context, song string, group string) (
 	return r0, r1
 }
 
+// CreateBatch provides a mock function with given fields: ctx, songs
+func (_m *ServiceIface) CreateBatch(ctx context.Context, songs []models.Song) ([]models.BatchResult, error) {
+	ret := _m.Called(ctx, songs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateBatch")
+	}
+
+	var r0 []models.BatchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.Song) ([]models.BatchResult, error)); ok {
+		return rf(ctx, songs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []models.Song) []models.BatchResult); ok {
+		r0 = rf(ctx, songs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.BatchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []models.Song) error); ok {
+		r1 = rf(ctx, songs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields: ctx, id
 func (_m *ServiceIface) Delete(ctx context.Context, id int) (bool, error) {
 	ret := _m.Called(ctx, id)
@@ -70,6 +102,80 @@ func (_m *ServiceIface) Delete(ctx context.Context, id int) (bool, error) {
 	return r0, r1
 }
 
+// DeleteBatch provides a mock function with given fields: ctx, filters
+func (_m *ServiceIface) DeleteBatch(ctx context.Context, filters models.DeleteFilters) (models.DeleteResult, error) {
+	ret := _m.Called(ctx, filters)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBatch")
+	}
+
+	var r0 models.DeleteResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.DeleteFilters) (models.DeleteResult, error)); ok {
+		return rf(ctx, filters)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.DeleteFilters) models.DeleteResult); ok {
+		r0 = rf(ctx, filters)
+	} else {
+		r0 = ret.Get(0).(models.DeleteResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.DeleteFilters) error); ok {
+		r1 = rf(ctx, filters)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteTranslation provides a mock function with given fields: ctx, id, lang
+func (_m *ServiceIface) DeleteTranslation(ctx context.Context, id int, lang string) (bool, error) {
+	ret := _m.Called(ctx, id, lang)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTranslation")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (bool, error)); ok {
+		return rf(ctx, id, lang)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) bool); ok {
+		r0 = rf(ctx, id, lang)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, id, lang)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Export provides a mock function with given fields: ctx, w, format, filters
+func (_m *ServiceIface) Export(ctx context.Context, w io.Writer, format string, filters models.GetFilters) error {
+	ret := _m.Called(ctx, w, format, filters)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Export")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.Writer, string, models.GetFilters) error); ok {
+		r0 = rf(ctx, w, format, filters)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetAll provides a mock function with given fields: ctx, filters
 func (_m *ServiceIface) GetAll(ctx context.Context, filters models.GetFilters) ([]models.Song, error) {
 	ret := _m.Called(ctx, filters)
@@ -100,6 +206,166 @@ func (_m *ServiceIface) GetAll(ctx context.Context, filters models.GetFilters) (
 	return r0, r1
 }
 
+// GetLyrics provides a mock function with given fields: ctx, id
+func (_m *ServiceIface) GetLyrics(ctx context.Context, id int) (models.Lyrics, bool, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLyrics")
+	}
+
+	var r0 models.Lyrics
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (models.Lyrics, bool, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) models.Lyrics); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.Lyrics)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) bool); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int) error); ok {
+		r2 = rf(ctx, id)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetSections provides a mock function with given fields: ctx, filters
+func (_m *ServiceIface) GetSections(ctx context.Context, filters models.GetVersesFilters) ([]models.Section, error) {
+	ret := _m.Called(ctx, filters)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSections")
+	}
+
+	var r0 []models.Section
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.GetVersesFilters) ([]models.Section, error)); ok {
+		return rf(ctx, filters)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.GetVersesFilters) []models.Section); ok {
+		r0 = rf(ctx, filters)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Section)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.GetVersesFilters) error); ok {
+		r1 = rf(ctx, filters)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTimedVerses provides a mock function with given fields: ctx, filters
+func (_m *ServiceIface) GetTimedVerses(ctx context.Context, filters models.GetVersesFilters) ([]models.Verse, error) {
+	ret := _m.Called(ctx, filters)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTimedVerses")
+	}
+
+	var r0 []models.Verse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.GetVersesFilters) ([]models.Verse, error)); ok {
+		return rf(ctx, filters)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.GetVersesFilters) []models.Verse); ok {
+		r0 = rf(ctx, filters)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Verse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.GetVersesFilters) error); ok {
+		r1 = rf(ctx, filters)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTranslation provides a mock function with given fields: ctx, id, lang
+func (_m *ServiceIface) GetTranslation(ctx context.Context, id int, lang string) (models.Translation, bool, error) {
+	ret := _m.Called(ctx, id, lang)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTranslation")
+	}
+
+	var r0 models.Translation
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (models.Translation, bool, error)); ok {
+		return rf(ctx, id, lang)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) models.Translation); ok {
+		r0 = rf(ctx, id, lang)
+	} else {
+		r0 = ret.Get(0).(models.Translation)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) bool); ok {
+		r1 = rf(ctx, id, lang)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, string) error); ok {
+		r2 = rf(ctx, id, lang)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTranslations provides a mock function with given fields: ctx, id
+func (_m *ServiceIface) GetTranslations(ctx context.Context, id int) ([]string, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTranslations")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]string, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []string); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetVerses provides a mock function with given fields: ctx, filters
 func (_m *ServiceIface) GetVerses(ctx context.Context, filters models.GetVersesFilters) ([]string, error) {
 	ret := _m.Called(ctx, filters)
@@ -130,6 +396,138 @@ func (_m *ServiceIface) GetVerses(ctx context.Context, filters models.GetVersesF
 	return r0, r1
 }
 
+// Import provides a mock function with given fields: ctx, r, opts
+func (_m *ServiceIface) Import(ctx context.Context, r io.Reader, opts models.ImportOptions) (models.ImportReport, error) {
+	ret := _m.Called(ctx, r, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Import")
+	}
+
+	var r0 models.ImportReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, models.ImportOptions) (models.ImportReport, error)); ok {
+		return rf(ctx, r, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, models.ImportOptions) models.ImportReport); ok {
+		r0 = rf(ctx, r, opts)
+	} else {
+		r0 = ret.Get(0).(models.ImportReport)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, io.Reader, models.ImportOptions) error); ok {
+		r1 = rf(ctx, r, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetLyrics provides a mock function with given fields: ctx, id, lines
+func (_m *ServiceIface) SetLyrics(ctx context.Context, id int, lines []models.LyricLine) (bool, error) {
+	ret := _m.Called(ctx, id, lines)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetLyrics")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []models.LyricLine) (bool, error)); ok {
+		return rf(ctx, id, lines)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, []models.LyricLine) bool); ok {
+		r0 = rf(ctx, id, lines)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, []models.LyricLine) error); ok {
+		r1 = rf(ctx, id, lines)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetTranslation provides a mock function with given fields: ctx, translation
+func (_m *ServiceIface) SetTranslation(ctx context.Context, translation models.Translation) (bool, error) {
+	ret := _m.Called(ctx, translation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetTranslation")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Translation) (bool, error)); ok {
+		return rf(ctx, translation)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.Translation) bool); ok {
+		r0 = rf(ctx, translation)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.Translation) error); ok {
+		r1 = rf(ctx, translation)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SongVerses provides a mock function with given fields: ctx, song, filters
+func (_m *ServiceIface) SongVerses(ctx context.Context, song models.Song, filters models.GetVersesFilters) ([]string, error) {
+	ret := _m.Called(ctx, song, filters)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SongVerses")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Song, models.GetVersesFilters) ([]string, error)); ok {
+		return rf(ctx, song, filters)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.Song, models.GetVersesFilters) []string); ok {
+		r0 = rf(ctx, song, filters)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.Song, models.GetVersesFilters) error); ok {
+		r1 = rf(ctx, song, filters)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Stream provides a mock function with given fields: ctx, filters, fn
+func (_m *ServiceIface) Stream(ctx context.Context, filters models.GetFilters, fn func(models.Song) error) error {
+	ret := _m.Called(ctx, filters, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stream")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.GetFilters, func(models.Song) error) error); ok {
+		r0 = rf(ctx, filters, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, song
 func (_m *ServiceIface) Update(ctx context.Context, song models.Song) (bool, error) {
 	ret := _m.Called(ctx, song)
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/internal/storage"
+)
+
+// go run github.com/vektra/mockery/v2@v2.45.0 --name=PlaylistServiceIface
+type PlaylistServiceIface interface {
+	Create(ctx context.Context, name string) (models.Playlist, error)
+	Get(ctx context.Context, filters models.GetPlaylistFilters) (models.Playlist, bool, error)
+	Delete(ctx context.Context, id int) (bool, error)
+	AddSong(ctx context.Context, item models.PlaylistItem) (bool, error)
+	MoveSong(ctx context.Context, move models.PlaylistMove) (bool, error)
+	RemoveSong(ctx context.Context, item models.PlaylistItem) (bool, error)
+}
+
+type PlaylistService struct {
+	storage storage.PlaylistStorage
+}
+
+func NewPlaylistService(s storage.PlaylistStorage) PlaylistServiceIface {
+	return &PlaylistService{
+		storage: s,
+	}
+}
+
+func (s *PlaylistService) Create(ctx context.Context, name string) (models.Playlist, error) {
+	playlist := models.Playlist{Name: name}
+
+	id, err := s.storage.Create(ctx, playlist)
+	if err != nil {
+		return models.Playlist{}, err
+	}
+
+	playlist.Id = id
+
+	return playlist, nil
+}
+
+func (s *PlaylistService) Get(ctx context.Context, filters models.GetPlaylistFilters) (models.Playlist, bool, error) {
+	return s.storage.Get(ctx, filters)
+}
+
+func (s *PlaylistService) Delete(ctx context.Context, id int) (bool, error) {
+	return s.storage.Delete(ctx, id)
+}
+
+func (s *PlaylistService) AddSong(ctx context.Context, item models.PlaylistItem) (bool, error) {
+	return s.storage.AddSong(ctx, item)
+}
+
+func (s *PlaylistService) MoveSong(ctx context.Context, move models.PlaylistMove) (bool, error) {
+	return s.storage.MoveSong(ctx, move)
+}
+
+func (s *PlaylistService) RemoveSong(ctx context.Context, item models.PlaylistItem) (bool, error) {
+	return s.storage.RemoveSong(ctx, item)
+}
@@ -2,36 +2,67 @@ package service
 
 import (
 	"context"
-	"strings"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
 
 	"github.com/s3nn1k/ef-mob-task/internal/client"
 	"github.com/s3nn1k/ef-mob-task/internal/models"
 	"github.com/s3nn1k/ef-mob-task/internal/storage"
 	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+	"go.opentelemetry.io/otel"
 )
 
+// tracer creates spans of service methods
+var tracer = otel.Tracer("github.com/s3nn1k/ef-mob-task/internal/service")
+
 // go run github.com/vektra/mockery/v2@v2.45.0 --name=ServiceIface
 type ServiceIface interface {
 	Create(ctx context.Context, song string, group string) (models.Song, error)
+	CreateBatch(ctx context.Context, songs []models.Song) ([]models.BatchResult, error)
+	Import(ctx context.Context, r io.Reader, opts models.ImportOptions) (models.ImportReport, error)
+	Export(ctx context.Context, w io.Writer, format string, filters models.GetFilters) error
+	Stream(ctx context.Context, filters models.GetFilters, fn func(models.Song) error) error
 	Update(ctx context.Context, song models.Song) (bool, error)
 	GetAll(ctx context.Context, filters models.GetFilters) ([]models.Song, error)
 	GetVerses(ctx context.Context, filters models.GetVersesFilters) ([]string, error)
+	SongVerses(ctx context.Context, song models.Song, filters models.GetVersesFilters) ([]string, error)
+	GetSections(ctx context.Context, filters models.GetVersesFilters) ([]models.Section, error)
+	GetTimedVerses(ctx context.Context, filters models.GetVersesFilters) ([]models.Verse, error)
+	SetLyrics(ctx context.Context, id int, lines []models.LyricLine) (bool, error)
+	GetLyrics(ctx context.Context, id int) (models.Lyrics, bool, error)
+	SetTranslation(ctx context.Context, translation models.Translation) (bool, error)
+	GetTranslation(ctx context.Context, id int, lang string) (models.Translation, bool, error)
+	GetTranslations(ctx context.Context, id int) ([]string, error)
+	DeleteTranslation(ctx context.Context, id int, lang string) (bool, error)
 	Delete(ctx context.Context, id int) (bool, error)
+	DeleteBatch(ctx context.Context, filters models.DeleteFilters) (models.DeleteResult, error)
 }
 
 type Service struct {
 	storage storage.Storage
 	client  client.ClientIface
+	limit   int
 }
 
-func New(s storage.Storage, c client.ClientIface) ServiceIface {
+// New creates service, limit is the maximum count of concurrent requests to API client while creating batch of songs
+func New(s storage.Storage, c client.ClientIface, limit int) ServiceIface {
+	if limit < 1 {
+		limit = 1
+	}
+
 	return &Service{
 		storage: s,
 		client:  c,
+		limit:   limit,
 	}
 }
 
 func (s *Service) Create(ctx context.Context, song string, group string) (models.Song, error) {
+	ctx, span := tracer.Start(ctx, "Service.Create")
+	defer span.End()
+
 	res, err := s.client.GetDetail(ctx, song, group)
 	if err != nil {
 		return models.Song{}, err
@@ -47,8 +78,79 @@ func (s *Service) Create(ctx context.Context, song string, group string) (models
 	return res, nil
 }
 
+// CreateBatch gets details of songs concurrently and creates all found songs at once
+// Songs that can't be found don't prevent creating of others, their errors are returned in results
+func (s *Service) CreateBatch(ctx context.Context, songs []models.Song) ([]models.BatchResult, error) {
+	ctx, span := tracer.Start(ctx, "Service.CreateBatch")
+	defer span.End()
+
+	logger.LogUse(ctx).Debug("Service.CreateBatch", slog.Int("count", len(songs)), slog.Int("limit", s.limit))
+
+	results := make([]models.BatchResult, len(songs))
+	details := make([]models.Song, len(songs))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, s.limit)
+
+	for i, song := range songs {
+		results[i].Index = i
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			res, err := s.client.GetDetail(ctx, song.Song, song.Group)
+			if err != nil {
+				logger.LogUse(ctx).Error(err.Error(), "input", song.LogValue())
+
+				results[i].Error = "Can't get song details"
+				return
+			}
+
+			details[i] = res
+		}()
+	}
+
+	wg.Wait()
+
+	var found []models.Song
+	var indexes []int
+
+	for i := range results {
+		if results[i].Error == "" {
+			found = append(found, details[i])
+			indexes = append(indexes, i)
+		}
+	}
+
+	ids, err := s.storage.CreateBatch(ctx, found)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) != len(found) {
+		return nil, fmt.Errorf("can't create songs: storage returned %d ids for %d songs", len(ids), len(found))
+	}
+
+	for j, i := range indexes {
+		song := found[j]
+		song.Id = ids[j]
+
+		results[i].Song = &song
+	}
+
+	return results, nil
+}
+
 func (s *Service) GetVerses(ctx context.Context, filters models.GetVersesFilters) ([]string, error) {
-	logger.LogUse(ctx).Debug("Service.GetById", "filters", filters.AsLogValue())
+	ctx, span := tracer.Start(ctx, "Service.GetVerses")
+	defer span.End()
+
+	logger.LogUse(ctx).Debug("Service.GetById", "filters", filters.LogValue())
 
 	songs, err := s.storage.GetAll(ctx, models.GetFilters{Limit: 1, Id: filters.Id})
 	if err != nil {
@@ -59,38 +161,178 @@ func (s *Service) GetVerses(ctx context.Context, filters models.GetVersesFilters
 		return nil, nil
 	}
 
-	logger.LogUse(ctx).Debug("Filter song's verses", "input", songs[0].Text)
+	return s.SongVerses(ctx, songs[0], filters)
+}
+
+// SongVerses returns verses of already loaded song, id of filters is replaced by song id
+// Used to get verses of many songs without loading every song separately
+func (s *Service) SongVerses(ctx context.Context, song models.Song, filters models.GetVersesFilters) ([]string, error) {
+	ctx, span := tracer.Start(ctx, "Service.SongVerses")
+	defer span.End()
+
+	filters.Id = song.Id
 
-	verses := filterVerses(songs[0].Text, filters.Limit, filters.Offset)
+	logger.LogUse(ctx).Debug("Filter song's verses", "input", logger.Text("text", song.Text))
+
+	sections, err := s.translate(ctx, filters, parseSections(song.Text))
+	if err != nil {
+		return nil, err
+	}
+
+	verses := filterVerses(sections, filters.Type, filters.Limit, filters.Offset)
 
 	logger.LogUse(ctx).Debug("Result", "verses", verses)
 
 	return verses, nil
 }
 
+func (s *Service) GetSections(ctx context.Context, filters models.GetVersesFilters) ([]models.Section, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetSections")
+	defer span.End()
+
+	logger.LogUse(ctx).Debug("Service.GetSections", "filters", filters.LogValue())
+
+	songs, err := s.storage.GetAll(ctx, models.GetFilters{Limit: 1, Id: filters.Id})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(songs) < 1 {
+		return nil, nil
+	}
+
+	logger.LogUse(ctx).Debug("Parse song's sections", "input", songs[0].Text)
+
+	sections, err := s.translate(ctx, filters, parseSections(songs[0].Text))
+	if err != nil {
+		return nil, err
+	}
+
+	sections = paginate(filterSections(sections, filters.Type), filters.Limit, filters.Offset)
+
+	logger.LogUse(ctx).Debug("Result", "sections", sections)
+
+	return sections, nil
+}
+
+func (s *Service) GetTimedVerses(ctx context.Context, filters models.GetVersesFilters) ([]models.Verse, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetTimedVerses")
+	defer span.End()
+
+	logger.LogUse(ctx).Debug("Service.GetTimedVerses", "filters", filters.LogValue())
+
+	songs, err := s.storage.GetAll(ctx, models.GetFilters{Limit: 1, Id: filters.Id})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(songs) < 1 {
+		return nil, nil
+	}
+
+	lines, err := s.storage.GetLyrics(ctx, filters.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	sections := parseSections(songs[0].Text)
+
+	// Time ranges are calculated for all sections, because lyrics lines are matched sequentially
+	verses := timeVerses(sections, lines)
+
+	if filters.Type != "" {
+		filtered := make([]models.Verse, 0, len(verses))
+
+		for i, verse := range verses {
+			if sections[i].Type == filters.Type {
+				filtered = append(filtered, verse)
+			}
+		}
+
+		verses = filtered
+	}
+
+	verses = paginate(verses, filters.Limit, filters.Offset)
+
+	logger.LogUse(ctx).Debug("Result", "verses", verses)
+
+	return verses, nil
+}
+
+func (s *Service) SetLyrics(ctx context.Context, id int, lines []models.LyricLine) (bool, error) {
+	ctx, span := tracer.Start(ctx, "Service.SetLyrics")
+	defer span.End()
+
+	return s.storage.SetLyrics(ctx, id, lines)
+}
+
+func (s *Service) GetLyrics(ctx context.Context, id int) (models.Lyrics, bool, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetLyrics")
+	defer span.End()
+
+	songs, err := s.storage.GetAll(ctx, models.GetFilters{Limit: 1, Id: id})
+	if err != nil {
+		return models.Lyrics{}, false, err
+	}
+
+	if len(songs) < 1 {
+		return models.Lyrics{}, false, nil
+	}
+
+	lines, err := s.storage.GetLyrics(ctx, id)
+	if err != nil {
+		return models.Lyrics{}, false, err
+	}
+
+	return models.Lyrics{
+		Id:    songs[0].Id,
+		Song:  songs[0].Song,
+		Group: songs[0].Group,
+		Lines: lines,
+	}, true, nil
+}
+
 func (s *Service) GetAll(ctx context.Context, filters models.GetFilters) ([]models.Song, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetAll")
+	defer span.End()
+
 	return s.storage.GetAll(ctx, filters)
 }
 
 func (s *Service) Update(ctx context.Context, song models.Song) (bool, error) {
+	ctx, span := tracer.Start(ctx, "Service.Update")
+	defer span.End()
+
 	return s.storage.Update(ctx, song)
 }
 
 func (s *Service) Delete(ctx context.Context, id int) (bool, error) {
+	ctx, span := tracer.Start(ctx, "Service.Delete")
+	defer span.End()
+
 	return s.storage.Delete(ctx, id)
 }
 
-func filterVerses(text string, limit int, offset int) []string {
-	verses := strings.Split(text, "\n\n")
+func (s *Service) DeleteBatch(ctx context.Context, filters models.DeleteFilters) (models.DeleteResult, error) {
+	ctx, span := tracer.Start(ctx, "Service.DeleteBatch")
+	defer span.End()
 
-	if len(verses) > offset {
-		verses = verses[offset:]
+	ids, err := s.storage.DeleteBatch(ctx, filters)
+	if err != nil {
+		return models.DeleteResult{}, err
+	}
 
-		if len(verses) > limit {
-			verses = verses[:limit]
-		}
-	} else {
-		return []string{}
+	return models.DeleteResult{Ids: ids, DryRun: filters.DryRun}, nil
+}
+
+// filterVerses returns paginated texts of song sections with the given type
+// Empty type means all sections
+func filterVerses(sections []models.Section, kind string, limit int, offset int) []string {
+	sections = paginate(filterSections(sections, kind), limit, offset)
+
+	verses := make([]string, 0, len(sections))
+	for _, section := range sections {
+		verses = append(verses, section.Text)
 	}
 
 	return verses
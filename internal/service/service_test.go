@@ -1,6 +1,16 @@
 package service
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+)
 
 func TestFilterVerses(t *testing.T) {
 	tests := []struct {
@@ -45,11 +55,25 @@ func TestFilterVerses(t *testing.T) {
 			offset:  0,
 			wantRes: []string{"text1", "text2", "text3", "text4", "text5"},
 		},
+		{
+			name:    "trailing",
+			text:    "first verse\n\nsecond verse\n\n",
+			limit:   10,
+			offset:  0,
+			wantRes: []string{"first verse", "second verse"},
+		},
+		{
+			name:    "crlf",
+			text:    "line1\r\nline2  \r\n \r\n\r\nline3",
+			limit:   10,
+			offset:  0,
+			wantRes: []string{"line1\nline2", "line3"},
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			verses := filterVerses(test.text, test.limit, test.offset)
+			verses := filterVerses(parseSections(test.text), "", test.limit, test.offset)
 
 			if len(verses) != len(test.wantRes) {
 				t.Fatalf("error: verses slice and wantRes must have the same length")
@@ -63,3 +87,343 @@ func TestFilterVerses(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSections(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantRes []models.Section
+	}{
+		{
+			name: "markers",
+			text: "[Intro]\nla-la\n\n[Verse 1: Artist]\nline1\nline2\n[Chorus]\nchorus\n\n[Verse 2]\nline3\n\n[Chorus]\n\n[Bridge]\nbridge",
+			wantRes: []models.Section{
+				{Type: models.SectionIntro, Label: "Intro", Text: "la-la"},
+				{Type: models.SectionVerse, Label: "Verse 1: Artist", Text: "line1\nline2"},
+				{Type: models.SectionChorus, Label: "Chorus", Text: "chorus"},
+				{Type: models.SectionVerse, Label: "Verse 2", Text: "line3"},
+				{Type: models.SectionChorus, Label: "Chorus", Text: "chorus"},
+				{Type: models.SectionBridge, Label: "Bridge", Text: "bridge"},
+			},
+		},
+		{
+			name: "repeated",
+			text: "verse1\n\nchorus\nchorus\n\nverse2\n\nchorus\nchorus\n\n",
+			wantRes: []models.Section{
+				{Type: models.SectionVerse, Label: "Verse 1", Text: "verse1"},
+				{Type: models.SectionChorus, Label: "Chorus", Text: "chorus\nchorus"},
+				{Type: models.SectionVerse, Label: "Verse 2", Text: "verse2"},
+				{Type: models.SectionChorus, Label: "Chorus", Text: "chorus\nchorus"},
+			},
+		},
+		{
+			name: "marker before empty line",
+			text: "[Pre-Chorus]\n\npre\n\n[Outro]",
+			wantRes: []models.Section{
+				{Type: models.SectionPreChorus, Label: "Pre-Chorus", Text: "pre"},
+			},
+		},
+		{
+			name:    "empty",
+			text:    "\n\n  \n",
+			wantRes: []models.Section{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sections := parseSections(test.text)
+
+			if len(sections) != len(test.wantRes) {
+				t.Fatalf("error: want %v sections, but got %v", len(test.wantRes), len(sections))
+			}
+
+			for i := 0; i < len(sections); i++ {
+				if sections[i] != test.wantRes[i] {
+					t.Fatalf("error: want %+v section with %v index, but got %+v", test.wantRes[i], i, sections[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFilterVersesByType(t *testing.T) {
+	text := "[Verse 1]\nverse1\n\n[Chorus]\nchorus\n\n[Verse 2]\nverse2\n\n[Chorus]"
+
+	verses := filterVerses(parseSections(text), models.SectionChorus, 10, 0)
+
+	if len(verses) != 2 {
+		t.Fatalf("error: want 2 choruses, but got %v", len(verses))
+	}
+
+	for _, verse := range verses {
+		if verse != "chorus" {
+			t.Fatalf("error: want chorus text, but got %s", verse)
+		}
+	}
+
+	verses = filterVerses(parseSections(text), models.SectionVerse, 1, 1)
+
+	if len(verses) != 1 || verses[0] != "verse2" {
+		t.Fatalf("error: want second verse, but got %v", verses)
+	}
+}
+
+func TestTimeVerses(t *testing.T) {
+	sections := []models.Section{
+		{Type: models.SectionVerse, Label: "Verse 1", Text: "line1\nline2"},
+		{Type: models.SectionChorus, Label: "Chorus", Text: "chorus"},
+		{Type: models.SectionVerse, Label: "Verse 2", Text: "line3"},
+	}
+
+	lines := []models.LyricLine{
+		{Time: 1000, Text: "line1"},
+		{Time: 2000, Text: "line2"},
+		{Time: 3000, Text: ""},
+		{Time: 4000, Text: "chorus"},
+		{Time: 5000, Text: "line3"},
+	}
+
+	wantRes := []models.Verse{
+		{Text: "line1\nline2", Start: 1000, End: 3000},
+		{Text: "chorus", Start: 4000, End: 5000},
+		{Text: "line3", Start: 5000, End: 5000},
+	}
+
+	verses := timeVerses(sections, lines)
+
+	if len(verses) != len(wantRes) {
+		t.Fatalf("error: want %v verses, but got %v", len(wantRes), len(verses))
+	}
+
+	for i := range verses {
+		if verses[i] != wantRes[i] {
+			t.Fatalf("error: want %+v verse with %v index, but got %+v", wantRes[i], i, verses[i])
+		}
+	}
+
+	verses = timeVerses(sections, nil)
+
+	for _, verse := range verses {
+		if verse.Start != 0 || verse.End != 0 {
+			t.Fatalf("error: verses without synchronized lyrics must have zero time range")
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name    string
+		accept  string
+		langs   []string
+		wantRes string
+	}{
+		{
+			name:    "exact",
+			accept:  "de-DE,de;q=0.9,en;q=0.8",
+			langs:   []string{"de-DE", "en"},
+			wantRes: "de-DE",
+		},
+		{
+			name:    "base language",
+			accept:  "ru-RU,ru;q=0.9",
+			langs:   []string{"en", "ru"},
+			wantRes: "ru",
+		},
+		{
+			name:    "no match",
+			accept:  "fr",
+			langs:   []string{"en", "ru"},
+			wantRes: "",
+		},
+		{
+			name:    "no translations",
+			accept:  "en",
+			wantRes: "",
+		},
+		{
+			name:    "invalid header",
+			accept:  "@@@",
+			langs:   []string{"en"},
+			wantRes: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if res := negotiate(test.accept, test.langs); res != test.wantRes {
+				t.Fatalf("error: want %q, but got %q", test.wantRes, res)
+			}
+		})
+	}
+}
+
+func TestAlignSections(t *testing.T) {
+	original := parseSections("[Verse 1]\nverse1\n\n[Chorus]\nchorus\n\n[Verse 2]\nverse2")
+	translated := parseSections("verse1 translated\n\nchorus translated")
+
+	wantRes := []models.Section{
+		{Type: models.SectionVerse, Label: "Verse 1", Text: "verse1 translated"},
+		{Type: models.SectionChorus, Label: "Chorus", Text: "chorus translated"},
+		{Type: models.SectionVerse, Label: "Verse 2", Text: "verse2"},
+	}
+
+	sections := alignSections(original, translated)
+
+	if len(sections) != len(wantRes) {
+		t.Fatalf("error: want %v sections, but got %v", len(wantRes), len(sections))
+	}
+
+	for i := range sections {
+		if sections[i] != wantRes[i] {
+			t.Fatalf("error: want %+v section with %v index, but got %+v", wantRes[i], i, sections[i])
+		}
+	}
+}
+
+func TestCsvReader(t *testing.T) {
+	file := "\ufeffSong,Group_Name,Release_Date,Extra\nTestSong, TestGroup ,16.07.2006,x\n\"broken,row\nOther,Group\n"
+
+	reader, err := newSongReader(strings.NewReader(file), models.FormatCsv)
+	if err != nil {
+		t.Fatalf("error not expected: %s", err)
+	}
+
+	song, n, err := reader.Read()
+	if err != nil {
+		t.Fatalf("error not expected: %s", err)
+	}
+
+	wantSong := models.Song{Song: "TestSong", Group: "TestGroup", Date: "16.07.2006"}
+
+	if n != 1 || song != wantSong {
+		t.Fatalf("error: want %+v song in row 1, but got %+v in row %v", wantSong, song, n)
+	}
+
+	if _, n, err := reader.Read(); err == nil || n != 2 {
+		t.Fatalf("error: want error in row 2, but got %v in row %v", err, n)
+	}
+
+	if _, _, err := reader.Read(); !errors.Is(err, io.EOF) {
+		t.Fatalf("error: want io.EOF, but got %v", err)
+	}
+
+	if _, err := newSongReader(strings.NewReader("title,artist\n"), models.FormatCsv); !errors.Is(err, ErrInvalidFile) {
+		t.Fatalf("error: want ErrInvalidFile for header without song and group, but got %v", err)
+	}
+
+	// Failed read of body is reported once and stops reading
+	body := io.MultiReader(strings.NewReader("song,group\nTestSong,TestGroup\n"), iotest.ErrReader(errors.New("read timeout")))
+
+	reader, err = newSongReader(body, models.FormatCsv)
+	if err != nil {
+		t.Fatalf("error not expected: %s", err)
+	}
+
+	if _, n, err := reader.Read(); err != nil || n != 1 {
+		t.Fatalf("error: want song in row 1, but got %v in row %v", err, n)
+	}
+
+	if _, n, err := reader.Read(); err == nil || n != 2 {
+		t.Fatalf("error: want read error in row 2, but got %v in row %v", err, n)
+	}
+
+	if _, _, err := reader.Read(); !errors.Is(err, io.EOF) {
+		t.Fatalf("error: want io.EOF after read error, but got %v", err)
+	}
+}
+
+func TestImportReadError(t *testing.T) {
+	srvc := New(nil, nil, 1)
+
+	body := io.MultiReader(strings.NewReader("song,group\n"), iotest.ErrReader(errors.New("read timeout")))
+
+	report, err := srvc.Import(context.Background(), body, models.ImportOptions{Format: models.FormatCsv})
+	if err != nil {
+		t.Fatalf("error not expected: %s", err)
+	}
+
+	if report.Total != 1 || report.Failed != 1 || len(report.Errors) != 1 {
+		t.Fatalf("error: want one failed row for read error, but got %+v", report)
+	}
+}
+
+func TestJsonlReader(t *testing.T) {
+	file := `{"id":5,"song":"TestSong","group":"TestGroup"}` + "\n\n{invalid\n" + `{"song":"Other","group":"Group"}`
+
+	reader, err := newSongReader(strings.NewReader(file), models.FormatJsonl)
+	if err != nil {
+		t.Fatalf("error not expected: %s", err)
+	}
+
+	wantRows := []struct {
+		n       int
+		song    models.Song
+		wantErr bool
+	}{
+		{n: 1, song: models.Song{Song: "TestSong", Group: "TestGroup"}},
+		{n: 3, wantErr: true},
+		{n: 4, song: models.Song{Song: "Other", Group: "Group"}},
+	}
+
+	for _, want := range wantRows {
+		song, n, err := reader.Read()
+		if (err != nil) != want.wantErr || n != want.n || song != want.song {
+			t.Fatalf("error: want %+v song in row %v, but got %+v in row %v with error %v", want.song, want.n, song, n, err)
+		}
+	}
+
+	if _, _, err := reader.Read(); !errors.Is(err, io.EOF) {
+		t.Fatalf("error: want io.EOF, but got %v", err)
+	}
+}
+
+func TestValidateSong(t *testing.T) {
+	tests := []struct {
+		name    string
+		song    models.Song
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			song: models.Song{Song: "TestSong", Group: "TestGroup", Date: "16.07.2006"},
+		},
+		{
+			name:    "empty group",
+			song:    models.Song{Song: "TestSong", Group: " "},
+			wantErr: true,
+		},
+		{
+			name:    "long link",
+			song:    models.Song{Song: "TestSong", Group: "TestGroup", Link: strings.Repeat("a", maxFieldLength+1)},
+			wantErr: true,
+		},
+		{
+			name:    "invalid date",
+			song:    models.Song{Song: "TestSong", Group: "TestGroup", Date: "2006-07-16"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := validateSong(test.song); (err != nil) != test.wantErr {
+				t.Fatalf("error: want error %v, but got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSongRecord(t *testing.T) {
+	song := models.Song{Id: 1, Song: "TestSong", Group: "TestGroup", Text: "TestText", Link: "TestLink", Date: "16.07.2006"}
+
+	want := []string{"1", "TestSong", "TestGroup", "TestText", "TestLink", "16.07.2006"}
+	if got := songRecord(song, csvHeader); !slices.Equal(got, want) {
+		t.Fatalf("error: want %v, but got %v", want, got)
+	}
+
+	want = []string{"1", "TestGroup"}
+	if got := songRecord(song, []string{models.FieldId, models.FieldGroup}); !slices.Equal(got, want) {
+		t.Fatalf("error: want %v, but got %v", want, got)
+	}
+}
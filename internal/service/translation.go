@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+	"golang.org/x/text/language"
+)
+
+func (s *Service) SetTranslation(ctx context.Context, translation models.Translation) (bool, error) {
+	ctx, span := tracer.Start(ctx, "Service.SetTranslation")
+	defer span.End()
+
+	return s.storage.SetTranslation(ctx, translation)
+}
+
+func (s *Service) GetTranslation(ctx context.Context, id int, lang string) (models.Translation, bool, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetTranslation")
+	defer span.End()
+
+	return s.storage.GetTranslation(ctx, id, lang)
+}
+
+// GetTranslations returns languages of all song translations
+// Returns nil if song not exists
+func (s *Service) GetTranslations(ctx context.Context, id int) ([]string, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetTranslations")
+	defer span.End()
+
+	songs, err := s.storage.GetAll(ctx, models.GetFilters{Limit: 1, Id: id})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(songs) < 1 {
+		return nil, nil
+	}
+
+	return s.storage.GetTranslationLangs(ctx, id)
+}
+
+func (s *Service) DeleteTranslation(ctx context.Context, id int, lang string) (bool, error) {
+	ctx, span := tracer.Start(ctx, "Service.DeleteTranslation")
+	defer span.End()
+
+	return s.storage.DeleteTranslation(ctx, id, lang)
+}
+
+// translate replaces text of sections with translation requested by filters
+// Explicit language has priority over Accept-Language, original sections are returned if translation not exists
+func (s *Service) translate(ctx context.Context, filters models.GetVersesFilters, sections []models.Section) ([]models.Section, error) {
+	lang := filters.Lang
+
+	if lang == "" && filters.Accept != "" {
+		langs, err := s.storage.GetTranslationLangs(ctx, filters.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		lang = negotiate(filters.Accept, langs)
+	}
+
+	if lang == "" {
+		return sections, nil
+	}
+
+	translation, ok, err := s.storage.GetTranslation(ctx, filters.Id, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		logger.LogUse(ctx).Debug("Translation not exists, use original text", "lang", lang)
+
+		return sections, nil
+	}
+
+	logger.LogUse(ctx).Debug("Use translation", "lang", lang)
+
+	return alignSections(sections, parseSections(translation.Text)), nil
+}
+
+// negotiate returns the best translation language for Accept-Language header
+// Returns empty string if original text should be used
+func negotiate(accept string, langs []string) string {
+	prefs, _, err := language.ParseAcceptLanguage(accept)
+	if err != nil || len(prefs) == 0 || len(langs) == 0 {
+		return ""
+	}
+
+	// Original text is the first supported tag, so it is used when nothing matches
+	tags := []language.Tag{language.Und}
+	for _, lang := range langs {
+		tags = append(tags, language.Make(lang))
+	}
+
+	_, idx, conf := language.NewMatcher(tags).Match(prefs...)
+	if idx == 0 || conf == language.No {
+		return ""
+	}
+
+	return langs[idx-1]
+}
+
+// alignSections returns original sections with text of translated sections at the same index
+// Sections that are missing in translation keep original text
+func alignSections(original []models.Section, translated []models.Section) []models.Section {
+	res := make([]models.Section, len(original))
+
+	for i, section := range original {
+		if i < len(translated) {
+			section.Text = translated[i].Text
+		}
+
+		res[i] = section
+	}
+
+	return res
+}
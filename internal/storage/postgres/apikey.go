@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+)
+
+type KeyStorage struct {
+	db PgxPoolIface
+}
+
+// Create stores API key by hash of it's secret
+func (s *KeyStorage) Create(ctx context.Context, key models.APIKey, hash string) (models.APIKey, error) {
+	logger.LogUse(ctx).Debug("Storage.Postgres.Keys.Create", "input", key.LogValue())
+
+	query := fmt.Sprintf("INSERT INTO %s (name, prefix, hash, scope) VALUES (@name, @prefix, @hash, @scope) RETURNING id, created_at", keysTable)
+
+	args := pgx.NamedArgs{
+		"name":   key.Name,
+		"prefix": key.Prefix,
+		"hash":   hash,
+		"scope":  key.Scope,
+	}
+
+	if err := s.db.QueryRow(ctx, query, args).Scan(&key.Id, &key.Created); err != nil {
+		return models.APIKey{}, fmt.Errorf("can't create api key in storage: %w", err)
+	}
+
+	logger.LogUse(ctx).Debug("Result", slog.Int("id", key.Id))
+
+	return key, nil
+}
+
+// GetByHash returns not revoked API key by hash of it's secret
+func (s *KeyStorage) GetByHash(ctx context.Context, hash string) (models.APIKey, bool, error) {
+	logger.LogUse(ctx).Debug("Storage.Postgres.Keys.GetByHash")
+
+	query := fmt.Sprintf("SELECT id, name, prefix, scope, created_at FROM %s WHERE hash=@hash AND revoked_at IS NULL", keysTable)
+
+	args := pgx.NamedArgs{
+		"hash": hash,
+	}
+
+	var key models.APIKey
+
+	err := s.db.QueryRow(ctx, query, args).Scan(&key.Id, &key.Name, &key.Prefix, &key.Scope, &key.Created)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.LogUse(ctx).Debug("Result", slog.Bool("found", false))
+
+			return models.APIKey{}, false, nil
+		}
+
+		return models.APIKey{}, false, fmt.Errorf("can't get api key from storage: %w", err)
+	}
+
+	logger.LogUse(ctx).Debug("Result", "key", key.LogValue())
+
+	return key, true, nil
+}
+
+// List returns all API keys including revoked ones ordered by id
+func (s *KeyStorage) List(ctx context.Context) ([]models.APIKey, error) {
+	logger.LogUse(ctx).Debug("Storage.Postgres.Keys.List")
+
+	query := fmt.Sprintf("SELECT id, name, prefix, scope, created_at, revoked_at FROM %s ORDER BY id", keysTable)
+
+	rows, err := s.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("can't get api keys from storage: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+
+	for rows.Next() {
+		var key models.APIKey
+
+		if err := rows.Scan(&key.Id, &key.Name, &key.Prefix, &key.Scope, &key.Created, &key.Revoked); err != nil {
+			return nil, fmt.Errorf("can't get api keys from storage: %w", err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("can't get api keys from storage: %w", err)
+	}
+
+	logger.LogUse(ctx).Debug("Result", slog.Int("count", len(keys)))
+
+	return keys, nil
+}
+
+// Revoke marks API key as revoked
+// Returns false if key not exists or already revoked
+func (s *KeyStorage) Revoke(ctx context.Context, id int) (bool, error) {
+	logger.LogUse(ctx).Debug("Storage.Postgres.Keys.Revoke", "input", slog.Int("id", id))
+
+	query := fmt.Sprintf("UPDATE %s SET revoked_at=now() WHERE id=@id AND revoked_at IS NULL", keysTable)
+
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+
+	rows, err := s.db.Exec(ctx, query, args)
+	if err != nil {
+		return false, fmt.Errorf("can't revoke api key in storage: %w", err)
+	}
+
+	res := rows.RowsAffected() > 0
+
+	logger.LogUse(ctx).Debug("Result", slog.Bool("revoked", res))
+
+	return res, nil
+}
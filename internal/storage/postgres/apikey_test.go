@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+)
+
+func TestCreateKey(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := models.APIKey{
+		Name:   "TestName",
+		Prefix: "sk_abcdefgh",
+		Scope:  models.ScopeWrite,
+	}
+
+	mock.ExpectQuery("^INSERT INTO api_keys (.+) RETURNING id, created_at$").
+		WithArgs(key.Name, key.Prefix, "TestHash", key.Scope).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow(1, time.Now()))
+
+	db := NewKeyStorage(mock)
+
+	stored, err := db.Create(context.Background(), key, "TestHash")
+	if err != nil {
+		t.Fatalf("error not expected while creating key: %s", err)
+	}
+
+	if stored.Id != 1 || stored.Created.IsZero() {
+		t.Fatalf("error: id and creation time must be returned, but got %+v", stored)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGetKeyByHash(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectQuery("^SELECT (.+) FROM api_keys WHERE hash=(.+) AND revoked_at IS NULL$").
+		WithArgs("TestHash").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "prefix", "scope", "created_at"}).
+			AddRow(1, "TestName", "sk_abcdefgh", models.ScopeRead, time.Now()))
+
+	mock.ExpectQuery("^SELECT (.+) FROM api_keys WHERE hash=(.+) AND revoked_at IS NULL$").
+		WithArgs("Unknown").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "prefix", "scope", "created_at"}))
+
+	db := NewKeyStorage(mock)
+
+	key, ok, err := db.GetByHash(context.Background(), "TestHash")
+	if err != nil || !ok || key.Scope != models.ScopeRead {
+		t.Fatalf("error: key must be found, but got %+v, %v, %v", key, ok, err)
+	}
+
+	if _, ok, err := db.GetByHash(context.Background(), "Unknown"); err != nil || ok {
+		t.Fatalf("error: key must not be found, but got %v, %v", ok, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestRevokeKey(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectExec("^UPDATE api_keys SET revoked_at=now(.+) WHERE (.+)$").
+		WithArgs(1).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	db := NewKeyStorage(mock)
+
+	ok, err := db.Revoke(context.Background(), 1)
+	if err != nil || !ok {
+		t.Fatalf("error: key must be revoked, but got %v, %v", ok, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+)
+
+// SetLyrics replaces synchronized lyrics of song with the given lines
+// Returns false if song not exists
+func (s *Storage) SetLyrics(ctx context.Context, id int, lines []models.LyricLine) (bool, error) {
+	logger.LogUse(ctx).Debug("Storage.Postgres.SetLyrics", slog.Int("id", id), slog.Int("lines", len(lines)))
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("can't set lyrics in storage: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := fmt.Sprintf("SELECT id FROM %s WHERE id=@id FOR UPDATE", table)
+
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+
+	var locked int
+
+	if err := tx.QueryRow(ctx, query, args).Scan(&locked); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.LogUse(ctx).Debug("Result", slog.Bool("set", false))
+
+			return false, nil
+		}
+
+		return false, fmt.Errorf("can't set lyrics in storage: %w", err)
+	}
+
+	query = fmt.Sprintf("DELETE FROM %s WHERE song_id=@id", lyricsTable)
+
+	if _, err := tx.Exec(ctx, query, args); err != nil {
+		return false, fmt.Errorf("can't set lyrics in storage: %w", err)
+	}
+
+	rows := make([][]any, 0, len(lines))
+	for i, line := range lines {
+		rows = append(rows, []any{id, i + 1, line.Time, line.Text})
+	}
+
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{lyricsTable}, []string{"song_id", "line", "time_ms", "text"}, pgx.CopyFromRows(rows))
+	if err != nil {
+		return false, fmt.Errorf("can't set lyrics in storage: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("can't set lyrics in storage: %w", err)
+	}
+
+	logger.LogUse(ctx).Debug("Result", slog.Bool("set", true))
+
+	return true, nil
+}
+
+// GetLyrics returns synchronized lyrics of song ordered by time
+func (s *Storage) GetLyrics(ctx context.Context, id int) ([]models.LyricLine, error) {
+	logger.LogUse(ctx).Debug("Storage.Postgres.GetLyrics", "input", slog.Int("id", id))
+
+	query := fmt.Sprintf("SELECT time_ms, text FROM %s WHERE song_id=@id ORDER BY line", lyricsTable)
+
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+
+	rows, err := s.db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("can't get lyrics from storage: %w", err)
+	}
+	defer rows.Close()
+
+	var lines []models.LyricLine
+
+	for rows.Next() {
+		var line models.LyricLine
+
+		if err := rows.Scan(&line.Time, &line.Text); err != nil {
+			return nil, fmt.Errorf("can't get lyrics from storage: %w", err)
+		}
+
+		lines = append(lines, line)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("can't get lyrics from storage: %w", err)
+	}
+
+	logger.LogUse(ctx).Debug("Result", slog.Int("lines", len(lines)))
+
+	return lines, nil
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+)
+
+func TestSetLyrics(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := 1
+
+	lines := []models.LyricLine{
+		{Time: 1000, Text: "first"},
+		{Time: 2000, Text: "second"},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("^SELECT id FROM songs WHERE (.+) FOR UPDATE$").
+		WithArgs(id).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(id))
+	mock.ExpectExec("^DELETE FROM song_lyrics WHERE (.+)$").
+		WithArgs(id).
+		WillReturnResult(pgxmock.NewResult("DELETE", 3))
+	mock.ExpectCopyFrom(pgx.Identifier{"song_lyrics"}, []string{"song_id", "line", "time_ms", "text"}).
+		WillReturnResult(int64(len(lines)))
+	mock.ExpectCommit()
+
+	db := NewStorage(mock)
+
+	ok, err := db.SetLyrics(context.Background(), id, lines)
+	if err != nil {
+		t.Fatalf("error not expected while setting lyrics: %s", err)
+	}
+
+	if !ok {
+		t.Fatal("error: result of setting lyrics must be true")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGetLyrics(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := 1
+
+	mock.ExpectQuery("^SELECT time_ms, text FROM song_lyrics WHERE (.+) ORDER BY line$").
+		WithArgs(id).
+		WillReturnRows(pgxmock.NewRows([]string{"time_ms", "text"}).
+			AddRow(int64(1000), "first").
+			AddRow(int64(2000), "second"))
+
+	db := NewStorage(mock)
+
+	lines, err := db.GetLyrics(context.Background(), id)
+	if err != nil {
+		t.Fatalf("error not expected while getting lyrics: %s", err)
+	}
+
+	if len(lines) != 2 || lines[0].Text != "first" || lines[1].Time != 2000 {
+		t.Fatalf("error: must get same lines as in storage, but got %+v", lines)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrationsTable is the table where golang-migrate stores applied version
+const migrationsTable = "schema_migrations"
+
+// SchemaVersion returns version of applied migrations and whether the last migration failed
+// Version is 0 if no migrations are applied
+func SchemaVersion(ctx context.Context, db PgxPoolIface) (uint, bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+
+	err := db.QueryRow(ctx, "SELECT version, dirty FROM "+migrationsTable+" LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+
+	if err != nil {
+		return 0, false, err
+	}
+
+	return uint(version), dirty, nil
+}
+
+// migrationsLockKey is the key of advisory lock, that is held by replica while it checks and applies migrations
+const migrationsLockKey int64 = 0x736f6e6773 // "songs"
+
+// LockMigrations waits for advisory lock of migrations, so only one replica applies them at once
+// Lock is held by dedicated connection until unlock is called or context is done
+func LockMigrations(ctx context.Context, db *pgxpool.Pool) (func(), error) {
+	conn, err := db.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationsLockKey); err != nil {
+		conn.Release()
+		return nil, err
+	}
+
+	unlock := func() {
+		// Connection is closed if lock can't be released, that releases lock too
+		if _, err := conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", migrationsLockKey); err != nil {
+			conn.Conn().Close(context.Background())
+		}
+
+		conn.Release()
+	}
+
+	return unlock, nil
+}
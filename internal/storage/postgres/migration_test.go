@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestSchemaVersion(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectQuery("^SELECT version, dirty FROM schema_migrations LIMIT 1$").
+		WillReturnRows(pgxmock.NewRows([]string{"version", "dirty"}).AddRow(int64(5), false))
+
+	version, dirty, err := SchemaVersion(context.Background(), mock)
+	if err != nil {
+		t.Fatalf("error not expected while getting version: %s", err)
+	}
+
+	if version != 5 || dirty {
+		t.Fatalf("error: expected clean version 5, got %d, dirty %t", version, dirty)
+	}
+
+	mock.ExpectQuery("^SELECT version, dirty FROM schema_migrations LIMIT 1$").
+		WillReturnError(pgx.ErrNoRows)
+
+	version, _, err = SchemaVersion(context.Background(), mock)
+	if err != nil {
+		t.Fatalf("error not expected without migrations: %s", err)
+	}
+
+	if version != 0 {
+		t.Fatalf("error: expected version 0 without migrations, got %d", version)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %s", err)
+	}
+}
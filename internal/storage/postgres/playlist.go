@@ -0,0 +1,345 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+)
+
+// foreignKeyViolation is a postgres error code for inserting row that references not existing row
+const foreignKeyViolation = "23503"
+
+type PlaylistStorage struct {
+	db PgxPoolIface
+}
+
+func (s *PlaylistStorage) Create(ctx context.Context, playlist models.Playlist) (int, error) {
+	logger.LogUse(ctx).Debug("Storage.Postgres.Playlist.Create", "input", playlist.LogValue())
+
+	query := fmt.Sprintf("INSERT INTO %s (name) VALUES (@name) RETURNING id", playlistsTable)
+
+	args := pgx.NamedArgs{
+		"name": playlist.Name,
+	}
+
+	var id int
+
+	err := s.db.QueryRow(ctx, query, args).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("can't create playlist in storage: %w", err)
+	}
+
+	logger.LogUse(ctx).Debug("Result", slog.Int("id", id))
+
+	return id, nil
+}
+
+func (s *PlaylistStorage) Get(ctx context.Context, filters models.GetPlaylistFilters) (models.Playlist, bool, error) {
+	logger.LogUse(ctx).Debug("Storage.Postgres.Playlist.Get", "input", filters.LogValue())
+
+	query := fmt.Sprintf("SELECT id, name FROM %s WHERE id=@id", playlistsTable)
+
+	args := pgx.NamedArgs{
+		"id": filters.Id,
+	}
+
+	var playlist models.Playlist
+
+	err := s.db.QueryRow(ctx, query, args).Scan(&playlist.Id, &playlist.Name)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Playlist{}, false, nil
+		}
+
+		return models.Playlist{}, false, fmt.Errorf("can't get playlist from storage: %w", err)
+	}
+
+	// Positions are calculated with ROW_NUMBER, so gaps that left after cascade deleting of songs are not visible
+	query = fmt.Sprintf(`SELECT i.position, s.id, s.song, s.group_name, s.text, s.link, s.date FROM `+
+		`(SELECT song_id, ROW_NUMBER() OVER (ORDER BY position) AS position FROM %s WHERE playlist_id=@id) AS i `+
+		`JOIN %s AS s ON s.id=i.song_id ORDER BY i.position LIMIT @limit OFFSET @offset`, itemsTable, table)
+
+	args = pgx.NamedArgs{
+		"id":     filters.Id,
+		"limit":  filters.Limit,
+		"offset": filters.Offset,
+	}
+
+	rows, err := s.db.Query(ctx, query, args)
+	if err != nil {
+		return models.Playlist{}, false, fmt.Errorf("can't get playlist songs from storage: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var song models.PlaylistSong
+
+		err := rows.Scan(&song.Position, &song.Id, &song.Song.Song, &song.Group, &song.Text, &song.Link, &song.Date)
+		if err != nil {
+			return models.Playlist{}, false, fmt.Errorf("can't get playlist songs from storage: %w", err)
+		}
+
+		playlist.Songs = append(playlist.Songs, song)
+	}
+
+	if err := rows.Err(); err != nil {
+		return models.Playlist{}, false, fmt.Errorf("can't get playlist songs from storage: %w", err)
+	}
+
+	logger.LogUse(ctx).Debug("Result", slog.Any("playlist", playlist.LogValue()))
+
+	return playlist, true, nil
+}
+
+func (s *PlaylistStorage) Delete(ctx context.Context, id int) (bool, error) {
+	logger.LogUse(ctx).Debug("Storage.Postgres.Playlist.Delete", "input", slog.Int("id", id))
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE id=@id", playlistsTable)
+
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+
+	rows, err := s.db.Exec(ctx, query, args)
+	if err != nil {
+		return false, fmt.Errorf("can't delete playlist from storage: %w", err)
+	}
+
+	res := true
+	if rows.RowsAffected() == 0 {
+		res = false
+	}
+
+	logger.LogUse(ctx).Debug("Result", slog.Bool("deleted", res))
+
+	return res, nil
+}
+
+// AddSong inserts song into playlist at the given position and shifts next songs
+// Position out of range means the end of playlist
+func (s *PlaylistStorage) AddSong(ctx context.Context, item models.PlaylistItem) (bool, error) {
+	logger.LogUse(ctx).Debug("Storage.Postgres.Playlist.AddSong", "input", item.LogValue())
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("can't add song to playlist: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	count, ok, err := prepare(ctx, tx, item.PlaylistId)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	if item.Position < 1 || item.Position > count+1 {
+		item.Position = count + 1
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET position=position+1 WHERE playlist_id=@id AND position>=@position", itemsTable)
+
+	args := pgx.NamedArgs{
+		"id":       item.PlaylistId,
+		"position": item.Position,
+	}
+
+	if _, err := tx.Exec(ctx, query, args); err != nil {
+		return false, fmt.Errorf("can't add song to playlist: %w", err)
+	}
+
+	query = fmt.Sprintf("INSERT INTO %s (playlist_id, song_id, position) VALUES (@id, @songId, @position)", itemsTable)
+
+	args = pgx.NamedArgs{
+		"id":       item.PlaylistId,
+		"songId":   item.SongId,
+		"position": item.Position,
+	}
+
+	if _, err := tx.Exec(ctx, query, args); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+			logger.LogUse(ctx).Debug("Result", slog.Bool("added", false))
+
+			return false, nil
+		}
+
+		return false, fmt.Errorf("can't add song to playlist: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("can't add song to playlist: %w", err)
+	}
+
+	logger.LogUse(ctx).Debug("Result", slog.Bool("added", true), slog.Int("position", item.Position))
+
+	return true, nil
+}
+
+// MoveSong moves song from one position to another and shifts songs between them
+// Target position out of range means the end of playlist
+func (s *PlaylistStorage) MoveSong(ctx context.Context, move models.PlaylistMove) (bool, error) {
+	logger.LogUse(ctx).Debug("Storage.Postgres.Playlist.MoveSong", "input", move.LogValue())
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("can't move song in playlist: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	count, ok, err := prepare(ctx, tx, move.PlaylistId)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	if move.From < 1 || move.From > count {
+		logger.LogUse(ctx).Debug("Result", slog.Bool("moved", false))
+
+		return false, nil
+	}
+
+	if move.To < 1 || move.To > count {
+		move.To = count
+	}
+
+	if move.From != move.To {
+		query := fmt.Sprintf("SELECT id FROM %s WHERE playlist_id=@id AND position=@from", itemsTable)
+
+		args := pgx.NamedArgs{
+			"id":   move.PlaylistId,
+			"from": move.From,
+		}
+
+		var itemId int
+
+		if err := tx.QueryRow(ctx, query, args).Scan(&itemId); err != nil {
+			return false, fmt.Errorf("can't move song in playlist: %w", err)
+		}
+
+		if move.From < move.To {
+			query = fmt.Sprintf("UPDATE %s SET position=position-1 WHERE playlist_id=@id AND position>@from AND position<=@to", itemsTable)
+		} else {
+			query = fmt.Sprintf("UPDATE %s SET position=position+1 WHERE playlist_id=@id AND position>=@to AND position<@from", itemsTable)
+		}
+
+		args = pgx.NamedArgs{
+			"id":   move.PlaylistId,
+			"from": move.From,
+			"to":   move.To,
+		}
+
+		if _, err := tx.Exec(ctx, query, args); err != nil {
+			return false, fmt.Errorf("can't move song in playlist: %w", err)
+		}
+
+		query = fmt.Sprintf("UPDATE %s SET position=@to WHERE id=@itemId", itemsTable)
+
+		args = pgx.NamedArgs{
+			"to":     move.To,
+			"itemId": itemId,
+		}
+
+		if _, err := tx.Exec(ctx, query, args); err != nil {
+			return false, fmt.Errorf("can't move song in playlist: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("can't move song in playlist: %w", err)
+	}
+
+	logger.LogUse(ctx).Debug("Result", slog.Bool("moved", true), slog.Int("position", move.To))
+
+	return true, nil
+}
+
+// RemoveSong removes song at the given position from playlist and shifts next songs
+func (s *PlaylistStorage) RemoveSong(ctx context.Context, item models.PlaylistItem) (bool, error) {
+	logger.LogUse(ctx).Debug("Storage.Postgres.Playlist.RemoveSong", "input", item.LogValue())
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("can't remove song from playlist: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, ok, err := prepare(ctx, tx, item.PlaylistId)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE playlist_id=@id AND position=@position", itemsTable)
+
+	args := pgx.NamedArgs{
+		"id":       item.PlaylistId,
+		"position": item.Position,
+	}
+
+	rows, err := tx.Exec(ctx, query, args)
+	if err != nil {
+		return false, fmt.Errorf("can't remove song from playlist: %w", err)
+	}
+
+	if rows.RowsAffected() == 0 {
+		logger.LogUse(ctx).Debug("Result", slog.Bool("removed", false))
+
+		return false, nil
+	}
+
+	query = fmt.Sprintf("UPDATE %s SET position=position-1 WHERE playlist_id=@id AND position>@position", itemsTable)
+
+	if _, err := tx.Exec(ctx, query, args); err != nil {
+		return false, fmt.Errorf("can't remove song from playlist: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("can't remove song from playlist: %w", err)
+	}
+
+	logger.LogUse(ctx).Debug("Result", slog.Bool("removed", true))
+
+	return true, nil
+}
+
+// prepare locks playlist for changes, closes gaps in positions that could be left
+// after cascade deleting of songs and returns count of songs in playlist
+// Returns false if playlist not exists
+func prepare(ctx context.Context, tx pgx.Tx, id int) (int, bool, error) {
+	query := fmt.Sprintf("SELECT id FROM %s WHERE id=@id FOR UPDATE", playlistsTable)
+
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+
+	var locked int
+
+	if err := tx.QueryRow(ctx, query, args).Scan(&locked); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, nil
+		}
+
+		return 0, false, fmt.Errorf("can't lock playlist: %w", err)
+	}
+
+	query = fmt.Sprintf(`UPDATE %[1]s AS i SET position=r.position FROM `+
+		`(SELECT id, ROW_NUMBER() OVER (ORDER BY position) AS position FROM %[1]s WHERE playlist_id=@id) AS r `+
+		`WHERE i.id=r.id AND i.position<>r.position`, itemsTable)
+
+	if _, err := tx.Exec(ctx, query, args); err != nil {
+		return 0, false, fmt.Errorf("can't renumber playlist songs: %w", err)
+	}
+
+	query = fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE playlist_id=@id", itemsTable)
+
+	var count int
+
+	if err := tx.QueryRow(ctx, query, args).Scan(&count); err != nil {
+		return 0, false, fmt.Errorf("can't count playlist songs: %w", err)
+	}
+
+	return count, true, nil
+}
@@ -0,0 +1,293 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+)
+
+func TestCreatePlaylist(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	playlist := models.Playlist{
+		Name: "TestPlaylist",
+	}
+
+	id := 1
+
+	mock.ExpectQuery("^INSERT INTO playlists (.+) RETURNING id$").
+		WithArgs(playlist.Name).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(id))
+
+	db := NewPlaylistStorage(mock)
+
+	storedId, err := db.Create(context.Background(), playlist)
+	if err != nil {
+		t.Fatalf("error not expected while creating: %s", err)
+	}
+
+	if storedId != id {
+		t.Fatal("error: id must be returned after creating")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGetPlaylist(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	song := models.Song{
+		Id:    3,
+		Song:  "TestSong",
+		Group: "TestGroup",
+		Text:  "TestText",
+		Link:  "TestLink",
+		Date:  "TestDate",
+	}
+
+	filters := models.GetPlaylistFilters{
+		Id:     1,
+		Limit:  10,
+		Offset: 0,
+	}
+
+	mock.ExpectQuery("^SELECT id, name FROM playlists WHERE (.+)$").
+		WithArgs(filters.Id).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name"}).AddRow(filters.Id, "TestPlaylist"))
+
+	mock.ExpectQuery("^SELECT (.+) FROM (.+) playlist_items (.+) JOIN songs (.+)$").
+		WithArgs(filters.Id, filters.Limit, filters.Offset).
+		WillReturnRows(pgxmock.NewRows([]string{"position", "id", "song", "group", "text", "link", "date"}).
+			AddRow(1, song.Id, song.Song, song.Group, song.Text, song.Link, song.Date).
+			AddRow(2, song.Id, song.Song, song.Group, song.Text, song.Link, song.Date))
+
+	db := NewPlaylistStorage(mock)
+
+	playlist, ok, err := db.Get(context.Background(), filters)
+	if err != nil {
+		t.Fatalf("error not expected while getting playlist: %s", err)
+	}
+
+	if !ok {
+		t.Fatal("error: playlist must exist")
+	}
+
+	if len(playlist.Songs) != 2 {
+		t.Fatal("error: must get same songs as in storage")
+	}
+
+	for i, storedSong := range playlist.Songs {
+		if storedSong.Position != i+1 {
+			t.Fatalf("error: want position %v, but got %v", i+1, storedSong.Position)
+		}
+
+		if storedSong.Song != song {
+			t.Fatalf("error: returned song must be the same as in storage")
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAddPlaylistSong(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	item := models.PlaylistItem{
+		PlaylistId: 1,
+		SongId:     3,
+		Position:   10,
+	}
+
+	count := 2
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("^SELECT id FROM playlists WHERE (.+) FOR UPDATE$").
+		WithArgs(item.PlaylistId).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(item.PlaylistId))
+	mock.ExpectExec("^UPDATE playlist_items AS i SET (.+)$").
+		WithArgs(item.PlaylistId).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	mock.ExpectQuery("^SELECT COUNT(.+) FROM playlist_items WHERE (.+)$").
+		WithArgs(item.PlaylistId).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(count))
+	mock.ExpectExec("^UPDATE playlist_items SET position=position\\+1 WHERE (.+)$").
+		WithArgs(item.PlaylistId, count+1).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	mock.ExpectExec("^INSERT INTO playlist_items (.+)$").
+		WithArgs(item.PlaylistId, item.SongId, count+1).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+
+	db := NewPlaylistStorage(mock)
+
+	ok, err := db.AddSong(context.Background(), item)
+	if err != nil {
+		t.Fatalf("error not expected while adding song: %s", err)
+	}
+
+	if !ok {
+		t.Fatal("error: result of adding must be true")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestFailAddPlaylistSong(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	item := models.PlaylistItem{
+		PlaylistId: 1,
+		SongId:     3,
+		Position:   1,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("^SELECT id FROM playlists WHERE (.+) FOR UPDATE$").
+		WithArgs(item.PlaylistId).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(item.PlaylistId))
+	mock.ExpectExec("^UPDATE playlist_items AS i SET (.+)$").
+		WithArgs(item.PlaylistId).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	mock.ExpectQuery("^SELECT COUNT(.+) FROM playlist_items WHERE (.+)$").
+		WithArgs(item.PlaylistId).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec("^UPDATE playlist_items SET position=position\\+1 WHERE (.+)$").
+		WithArgs(item.PlaylistId, item.Position).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	mock.ExpectExec("^INSERT INTO playlist_items (.+)$").
+		WithArgs(item.PlaylistId, item.SongId, item.Position).
+		WillReturnError(&pgconn.PgError{Code: foreignKeyViolation})
+	mock.ExpectRollback()
+
+	db := NewPlaylistStorage(mock)
+
+	ok, err := db.AddSong(context.Background(), item)
+	if err != nil {
+		t.Fatalf("error not expected while adding not existing song: %s", err)
+	}
+
+	if ok {
+		t.Fatal("error: result of adding not existing song must be false")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMovePlaylistSong(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	move := models.PlaylistMove{
+		PlaylistId: 1,
+		From:       3,
+		To:         1,
+	}
+
+	itemId := 7
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("^SELECT id FROM playlists WHERE (.+) FOR UPDATE$").
+		WithArgs(move.PlaylistId).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(move.PlaylistId))
+	mock.ExpectExec("^UPDATE playlist_items AS i SET (.+)$").
+		WithArgs(move.PlaylistId).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	mock.ExpectQuery("^SELECT COUNT(.+) FROM playlist_items WHERE (.+)$").
+		WithArgs(move.PlaylistId).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery("^SELECT id FROM playlist_items WHERE (.+)$").
+		WithArgs(move.PlaylistId, move.From).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(itemId))
+	mock.ExpectExec("^UPDATE playlist_items SET position=position\\+1 WHERE (.+)$").
+		WithArgs(move.PlaylistId, move.To, move.From).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 2))
+	mock.ExpectExec("^UPDATE playlist_items SET position=(.+) WHERE id=(.+)$").
+		WithArgs(move.To, itemId).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectCommit()
+
+	db := NewPlaylistStorage(mock)
+
+	ok, err := db.MoveSong(context.Background(), move)
+	if err != nil {
+		t.Fatalf("error not expected while moving song: %s", err)
+	}
+
+	if !ok {
+		t.Fatal("error: result of moving must be true")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestRemovePlaylistSong(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	item := models.PlaylistItem{
+		PlaylistId: 1,
+		Position:   2,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("^SELECT id FROM playlists WHERE (.+) FOR UPDATE$").
+		WithArgs(item.PlaylistId).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(item.PlaylistId))
+	mock.ExpectExec("^UPDATE playlist_items AS i SET (.+)$").
+		WithArgs(item.PlaylistId).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	mock.ExpectQuery("^SELECT COUNT(.+) FROM playlist_items WHERE (.+)$").
+		WithArgs(item.PlaylistId).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectExec("^DELETE FROM playlist_items WHERE (.+)$").
+		WithArgs(item.PlaylistId, item.Position).
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectExec("^UPDATE playlist_items SET position=position-1 WHERE (.+)$").
+		WithArgs(item.PlaylistId, item.Position).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectCommit()
+
+	db := NewPlaylistStorage(mock)
+
+	ok, err := db.RemoveSong(context.Background(), item)
+	if err != nil {
+		t.Fatalf("error not expected while removing song: %s", err)
+	}
+
+	if !ok {
+		t.Fatal("error: result of removing must be true")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %s", err)
+	}
+}
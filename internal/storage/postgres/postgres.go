@@ -10,7 +10,12 @@ import (
 )
 
 const (
-	table = "songs"
+	table          = "songs"
+	playlistsTable = "playlists"
+	itemsTable     = "playlist_items"
+	lyricsTable    = "song_lyrics"
+	transTable     = "song_translations"
+	keysTable      = "api_keys"
 )
 
 // PgxPoolIface represents pgxpool.pool with only neccessary func's only
@@ -19,6 +24,7 @@ type PgxPoolIface interface {
 	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
 	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
 	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Begin(ctx context.Context) (pgx.Tx, error)
 }
 
 func NewStorage(db PgxPoolIface) storage.Storage {
@@ -27,8 +33,22 @@ func NewStorage(db PgxPoolIface) storage.Storage {
 	}
 }
 
+func NewPlaylistStorage(db PgxPoolIface) storage.PlaylistStorage {
+	return &PlaylistStorage{
+		db: db,
+	}
+}
+
+// ConnectDB creates pool of connections, every query is traced
 func ConnectDB(connStr string) (*pgxpool.Pool, error) {
-	pool, err := pgxpool.New(context.Background(), connStr)
+	cfg, err := pgxpool.ParseConfig(connStr)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.ConnConfig.Tracer = &queryTracer{}
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -40,3 +60,9 @@ func ConnectDB(connStr string) (*pgxpool.Pool, error) {
 
 	return pool, nil
 }
+
+func NewKeyStorage(db PgxPoolIface) storage.KeyStorage {
+	return &KeyStorage{
+		db: db,
+	}
+}
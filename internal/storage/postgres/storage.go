@@ -16,7 +16,7 @@ type Storage struct {
 }
 
 func (s *Storage) Create(ctx context.Context, song models.Song) (int, error) {
-	logger.LogUse(ctx).Debug("Storage.Postgres.Create", "input", song.AsLogValue())
+	logger.LogUse(ctx).Debug("Storage.Postgres.Create", "input", song.LogValue())
 
 	query := fmt.Sprintf("INSERT INTO %s (song, group_name, text, link, date) VALUES (@song, @group, @text, @link, @date) RETURNING id", table)
 	args := pgx.NamedArgs{
@@ -38,8 +38,79 @@ func (s *Storage) Create(ctx context.Context, song models.Song) (int, error) {
 	return id, nil
 }
 
+// CreateBatch creates songs with single insert from arrays of their fields
+// Returns ids in the same order as songs
+func (s *Storage) CreateBatch(ctx context.Context, songs []models.Song) ([]int, error) {
+	logger.LogUse(ctx).Debug("Storage.Postgres.CreateBatch", slog.Int("count", len(songs)))
+
+	if len(songs) == 0 {
+		return []int{}, nil
+	}
+
+	names := make([]string, 0, len(songs))
+	groups := make([]string, 0, len(songs))
+	texts := make([]string, 0, len(songs))
+	links := make([]string, 0, len(songs))
+	dates := make([]string, 0, len(songs))
+
+	for _, song := range songs {
+		names = append(names, song.Song)
+		groups = append(groups, song.Group)
+		texts = append(texts, song.Text)
+		links = append(links, song.Link)
+		dates = append(dates, song.Date)
+	}
+
+	args := pgx.NamedArgs{
+		"songs":  names,
+		"groups": groups,
+		"texts":  texts,
+		"links":  links,
+		"dates":  dates,
+	}
+
+	// Order of rows returned by insert isn't guaranteed, so ids are taken before insert
+	// and matched with ordinal of song in arrays
+	query := fmt.Sprintf(`WITH input AS (
+	SELECT nextval(pg_get_serial_sequence('%[1]s', 'id')) AS id, n, song, group_name, text, link, date
+	FROM unnest(@songs::text[], @groups::text[], @texts::text[], @links::text[], @dates::text[])
+		WITH ORDINALITY AS s(song, group_name, text, link, date, n)
+), inserted AS (
+	INSERT INTO %[1]s (id, song, group_name, text, link, date)
+	SELECT id, song, group_name, text, link, date FROM input
+	RETURNING id
+)
+SELECT inserted.id FROM inserted JOIN input USING (id) ORDER BY input.n`, table)
+
+	rows, err := s.db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("can't create songs in storage: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]int, 0, len(songs))
+
+	for rows.Next() {
+		var id int
+
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("can't create songs in storage: %w", err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("can't create songs in storage: %w", err)
+	}
+
+	logger.LogUse(ctx).Debug("Result", slog.Any("ids", ids))
+
+	return ids, nil
+}
+
 func (s *Storage) Update(ctx context.Context, song models.Song) (bool, error) {
-	logger.LogUse(ctx).Debug("Storage.Postgres.Update", "input", song.AsLogValue())
+	logger.LogUse(ctx).Debug("Storage.Postgres.Update", "input", song.LogValue())
 
 	query := fmt.Sprintf("UPDATE %s SET song=@song, group_name=@group, text=@text, link=@link, date=@date WHERE id=@id", table)
 	args := pgx.NamedArgs{
@@ -90,8 +161,76 @@ func (s *Storage) Delete(ctx context.Context, id int) (bool, error) {
 	return res, nil
 }
 
+// DeleteBatch deletes all songs that match filters and returns their ids
+// Songs are only selected without deleting in dry run
+func (s *Storage) DeleteBatch(ctx context.Context, filters models.DeleteFilters) ([]int, error) {
+	logger.LogUse(ctx).Debug("Storage.Postgres.DeleteBatch", "input", filters.LogValue())
+
+	var queryArgs []string
+	args := pgx.NamedArgs{}
+
+	if len(filters.Ids) > 0 {
+		queryArgs = append(queryArgs, "id=ANY(@ids)")
+		args["ids"] = filters.Ids
+	}
+
+	if filters.Song != "" {
+		queryArgs = append(queryArgs, "song=@song")
+		args["song"] = filters.Song
+	}
+
+	if filters.Group != "" {
+		queryArgs = append(queryArgs, "group_name=@group")
+		args["group"] = filters.Group
+	}
+
+	if filters.Date != "" {
+		queryArgs = append(queryArgs, "date=@date")
+		args["date"] = filters.Date
+	}
+
+	// Protects from deleting of all songs
+	if len(queryArgs) == 0 {
+		return nil, fmt.Errorf("can't delete songs from storage: filters are empty")
+	}
+
+	var query string
+
+	if filters.DryRun {
+		query = fmt.Sprintf("SELECT id FROM %s WHERE %s ORDER BY id", table, strings.Join(queryArgs, " AND "))
+	} else {
+		query = fmt.Sprintf("DELETE FROM %s WHERE %s RETURNING id", table, strings.Join(queryArgs, " AND "))
+	}
+
+	rows, err := s.db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("can't delete songs from storage: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+
+	for rows.Next() {
+		var id int
+
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("can't delete songs from storage: %w", err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("can't delete songs from storage: %w", err)
+	}
+
+	logger.LogUse(ctx).Debug("Result", slog.Any("ids", ids), slog.Bool("dryRun", filters.DryRun))
+
+	return ids, nil
+}
+
 func (s *Storage) GetAll(ctx context.Context, filters models.GetFilters) ([]models.Song, error) {
-	logger.LogUse(ctx).Debug("Storage.Postgres.GetAll", "input", filters.AsLogValue())
+	logger.LogUse(ctx).Debug("Storage.Postgres.GetAll", "input", filters.LogValue())
 
 	query, args := generateQuery(filters)
 	logger.LogUse(ctx).Debug("Generated", slog.Any("query", query), slog.Any("args", args))
@@ -102,27 +241,195 @@ func (s *Storage) GetAll(ctx context.Context, filters models.GetFilters) ([]mode
 	}
 
 	var songs []models.Song
-	var logValues []slog.Value
 	for rows.Next() {
 		var song models.Song
 
-		err := rows.Scan(&song.Id, &song.Song, &song.Group, &song.Text, &song.Link, &song.Date)
+		err := rows.Scan(scanTargets(&song, filters.Fields)...)
 		if err != nil {
 			return nil, fmt.Errorf("can't get songs from storage: %w", err)
 		}
 
 		songs = append(songs, song)
-		logValues = append(logValues, song.AsLogValue())
 	}
 
-	logger.LogUse(ctx).Debug("Result", slog.Any("songs", logValues))
+	// Songs are logged lazily and only as many as logging policy allows
+	logger.LogUse(ctx).Debug("Result", slog.Any("songs", logger.List(songs)))
 
 	return songs, nil
 }
 
+// Count returns count of all songs
+func (s *Storage) Count(ctx context.Context) (int, error) {
+	query := fmt.Sprintf("SELECT count(*) FROM %s", table)
+
+	var count int
+
+	if err := s.db.QueryRow(ctx, query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("can't count songs in storage: %w", err)
+	}
+
+	return count, nil
+}
+
+const (
+	// exportCursor is the name of server-side cursor used for export
+	exportCursor = "songs_export"
+	// exportFetchSize is the count of songs fetched from cursor at once
+	exportFetchSize = 100
+)
+
+// Export calls fn for every song that match filters in order of id
+// Songs are read through server-side cursor by parts, so all of them are never loaded into memory.
+// Limit and offset of filters are ignored
+func (s *Storage) Export(ctx context.Context, filters models.GetFilters, fn func(models.Song) error) error {
+	logger.LogUse(ctx).Debug("Storage.Postgres.Export", "input", filters.LogValue())
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("can't export songs from storage: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selectColumns(filters.Fields), ", "), table)
+
+	queryArgs, args := filterArgs(filters)
+	if len(queryArgs) > 0 {
+		query += " WHERE " + strings.Join(queryArgs, " AND ")
+	}
+
+	query = fmt.Sprintf("DECLARE %s NO SCROLL CURSOR FOR %s ORDER BY id", exportCursor, query)
+
+	if _, err := tx.Exec(ctx, query, args); err != nil {
+		return fmt.Errorf("can't export songs from storage: %w", err)
+	}
+
+	fetch := fmt.Sprintf("FETCH %d FROM %s", exportFetchSize, exportCursor)
+	count := 0
+
+	for {
+		rows, err := tx.Query(ctx, fetch)
+		if err != nil {
+			return fmt.Errorf("can't export songs from storage: %w", err)
+		}
+
+		songs := make([]models.Song, 0, exportFetchSize)
+
+		for rows.Next() {
+			var song models.Song
+
+			if err := rows.Scan(scanTargets(&song, filters.Fields)...); err != nil {
+				rows.Close()
+
+				return fmt.Errorf("can't export songs from storage: %w", err)
+			}
+
+			songs = append(songs, song)
+		}
+
+		rows.Close()
+
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("can't export songs from storage: %w", err)
+		}
+
+		// Rows are passed to fn after closing, because connection is busy until then
+		for _, song := range songs {
+			if err := fn(song); err != nil {
+				return err
+			}
+		}
+
+		count += len(songs)
+
+		if len(songs) < exportFetchSize {
+			break
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("can't export songs from storage: %w", err)
+	}
+
+	logger.LogUse(ctx).Debug("Result", slog.Int("exported", count))
+
+	return nil
+}
+
 // generateQuery generates sql query and []args use given arguments
+// Only columns of selected fields are queried
 func generateQuery(filters models.GetFilters) (string, pgx.NamedArgs) {
-	query := fmt.Sprintf("SELECT id, song, group_name, text, link, date FROM %s", table)
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selectColumns(filters.Fields), ", "), table)
+
+	queryArgs, args := filterArgs(filters)
+
+	if len(args) > 0 && len(queryArgs) > 0 {
+		subQuery := strings.Join(queryArgs, " AND ")
+
+		query += " WHERE " + subQuery
+	}
+
+	query += " LIMIT @limit"
+	args["limit"] = filters.Limit
+
+	query += " OFFSET @offset"
+	args["offset"] = filters.Offset
+
+	return query, args
+}
+
+// songColumns maps fields of song to columns of table
+var songColumns = map[string]string{
+	models.FieldId:          "id",
+	models.FieldSong:        "song",
+	models.FieldGroup:       "group_name",
+	models.FieldText:        "text",
+	models.FieldLink:        "link",
+	models.FieldReleaseDate: "date",
+}
+
+// selectColumns returns columns of the given fields, all columns are returned if fields are empty
+func selectColumns(fields []string) []string {
+	if len(fields) == 0 {
+		fields = models.SongFields
+	}
+
+	columns := make([]string, 0, len(fields))
+	for _, field := range fields {
+		columns = append(columns, songColumns[field])
+	}
+
+	return columns
+}
+
+// scanTargets returns pointers to fields of song in the same order as selectColumns
+func scanTargets(song *models.Song, fields []string) []any {
+	if len(fields) == 0 {
+		fields = models.SongFields
+	}
+
+	targets := make([]any, 0, len(fields))
+	for _, field := range fields {
+		switch field {
+		case models.FieldId:
+			targets = append(targets, &song.Id)
+		case models.FieldSong:
+			targets = append(targets, &song.Song)
+		case models.FieldGroup:
+			targets = append(targets, &song.Group)
+		case models.FieldText:
+			targets = append(targets, &song.Text)
+		case models.FieldLink:
+			targets = append(targets, &song.Link)
+		case models.FieldReleaseDate:
+			targets = append(targets, &song.Date)
+		}
+	}
+
+	return targets
+}
+
+// filterArgs returns conditions and args of where clause for the given filters
+func filterArgs(filters models.GetFilters) ([]string, pgx.NamedArgs) {
 	var queryArgs []string
 	args := pgx.NamedArgs{}
 
@@ -131,6 +438,11 @@ func generateQuery(filters models.GetFilters) (string, pgx.NamedArgs) {
 		args["id"] = filters.Id
 	}
 
+	if len(filters.Ids) > 0 {
+		queryArgs = append(queryArgs, "id=ANY(@ids)")
+		args["ids"] = filters.Ids
+	}
+
 	if filters.Song != "" {
 		queryArgs = append(queryArgs, "song=@song")
 		args["song"] = filters.Song
@@ -146,17 +458,5 @@ func generateQuery(filters models.GetFilters) (string, pgx.NamedArgs) {
 		args["date"] = filters.Date
 	}
 
-	if len(args) > 0 && len(queryArgs) > 0 {
-		subQuery := strings.Join(queryArgs, " AND ")
-
-		query += " WHERE " + subQuery
-	}
-
-	query += " LIMIT @limit"
-	args["limit"] = filters.Limit
-
-	query += " OFFSET @offset"
-	args["offset"] = filters.Offset
-
-	return query, args
+	return queryArgs, args
 }
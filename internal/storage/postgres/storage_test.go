@@ -179,3 +179,252 @@ func TestGetAll(t *testing.T) {
 		t.Fatalf("there were unfulfilled expectations: %s", err)
 	}
 }
+
+func TestGetAllByIds(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filters := models.GetFilters{
+		Limit: 2,
+		Ids:   []int{1, 2},
+	}
+
+	mock.ExpectQuery(`^SELECT (.+) FROM songs WHERE id=ANY\(@ids\) LIMIT @limit OFFSET @offset$`).
+		WithArgs(filters.Ids, filters.Limit, 0).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "song", "group", "text", "link", "date"}).
+			AddRow(2, "Second", "TestGroup", "", "", "").
+			AddRow(1, "First", "TestGroup", "", "", ""))
+
+	songs, err := NewStorage(mock).GetAll(context.Background(), filters)
+	if err != nil {
+		t.Fatalf("error not expected while get songs by ids: %s", err)
+	}
+
+	if len(songs) != 2 {
+		t.Fatalf("error: want 2 songs, but got %d", len(songs))
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGetAllFields(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filters := models.GetFilters{
+		Limit:  10,
+		Fields: []string{models.FieldId, models.FieldGroup, models.FieldReleaseDate},
+	}
+
+	mock.ExpectQuery(`^SELECT id, group_name, date FROM songs LIMIT @limit OFFSET @offset$`).
+		WithArgs(filters.Limit, 0).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "group", "date"}).
+			AddRow(1, "TestGroup", "16.07.2006"))
+
+	songs, err := NewStorage(mock).GetAll(context.Background(), filters)
+	if err != nil {
+		t.Fatalf("error not expected while get songs with fields: %s", err)
+	}
+
+	want := models.Song{Id: 1, Group: "TestGroup", Date: "16.07.2006"}
+	if len(songs) != 1 || songs[0] != want {
+		t.Fatalf("error: want only selected fields %+v, but got %+v", want, songs)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCreateBatch(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	songs := []models.Song{
+		{Song: "TestSong1", Group: "TestGroup", Text: "TestText", Link: "TestLink", Date: "TestDate"},
+		{Song: "TestSong2", Group: "TestGroup", Text: "TestText", Link: "TestLink", Date: "TestDate"},
+	}
+
+	mock.ExpectQuery(`(?s)^WITH input AS \(.+ WITH ORDINALITY .+ INSERT INTO songs .+ ORDER BY input.n$`).
+		WithArgs([]string{songs[0].Song, songs[1].Song}, []string{songs[0].Group, songs[1].Group}, []string{songs[0].Text, songs[1].Text},
+			[]string{songs[0].Link, songs[1].Link}, []string{songs[0].Date, songs[1].Date}).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+
+	db := NewStorage(mock)
+
+	ids, err := db.CreateBatch(context.Background(), songs)
+	if err != nil {
+		t.Fatalf("error not expected while creating batch: %s", err)
+	}
+
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+		t.Fatalf("error: ids must be returned in order of songs, but got %v", ids)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestDeleteBatch(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filters := models.DeleteFilters{
+		Ids:   []int{1, 2, 3},
+		Group: "TestGroup",
+	}
+
+	mock.ExpectQuery("^DELETE FROM songs WHERE (.+) RETURNING id$").
+		WithArgs(filters.Ids, filters.Group).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1).AddRow(3))
+
+	filters.DryRun = true
+
+	mock.ExpectQuery("^SELECT id FROM songs WHERE (.+) ORDER BY id$").
+		WithArgs(filters.Ids, filters.Group).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1).AddRow(3))
+
+	db := NewStorage(mock)
+
+	for _, dryRun := range []bool{false, true} {
+		filters.DryRun = dryRun
+
+		ids, err := db.DeleteBatch(context.Background(), filters)
+		if err != nil {
+			t.Fatalf("error not expected while deleting batch: %s", err)
+		}
+
+		if len(ids) != 2 {
+			t.Fatalf("error: must get ids of matched songs, but got %v", ids)
+		}
+	}
+
+	if _, err := db.DeleteBatch(context.Background(), models.DeleteFilters{}); err == nil {
+		t.Fatal("error expected while deleting with empty filters")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestExport(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filters := models.GetFilters{
+		Limit: 10,
+		Group: "TestGroup",
+	}
+
+	columns := []string{"id", "song", "group_name", "text", "link", "date"}
+
+	first := pgxmock.NewRows(columns)
+	for id := 1; id <= exportFetchSize; id++ {
+		first.AddRow(id, "TestSong", filters.Group, "TestText", "TestLink", "TestDate")
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^DECLARE songs_export NO SCROLL CURSOR FOR SELECT (.+) FROM songs WHERE (.+) ORDER BY id$").
+		WithArgs(filters.Group).
+		WillReturnResult(pgxmock.NewResult("DECLARE CURSOR", 0))
+	mock.ExpectQuery("^FETCH 100 FROM songs_export$").
+		WillReturnRows(first)
+	mock.ExpectQuery("^FETCH 100 FROM songs_export$").
+		WillReturnRows(pgxmock.NewRows(columns).AddRow(exportFetchSize+1, "TestSong", filters.Group, "TestText", "TestLink", "TestDate"))
+	mock.ExpectCommit()
+
+	db := NewStorage(mock)
+
+	var ids []int
+
+	err = db.Export(context.Background(), filters, func(song models.Song) error {
+		ids = append(ids, song.Id)
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("error not expected while exporting: %s", err)
+	}
+
+	if len(ids) != exportFetchSize+1 || ids[len(ids)-1] != exportFetchSize+1 {
+		t.Fatalf("error: must export all songs in order, but got %v", ids)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestExportFields(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filters := models.GetFilters{
+		Fields: []string{models.FieldId, models.FieldSong},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("^DECLARE songs_export NO SCROLL CURSOR FOR SELECT id, song FROM songs ORDER BY id$").
+		WillReturnResult(pgxmock.NewResult("DECLARE CURSOR", 0))
+	mock.ExpectQuery("^FETCH 100 FROM songs_export$").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "song"}).AddRow(1, "TestSong"))
+	mock.ExpectCommit()
+
+	db := NewStorage(mock)
+
+	var songs []models.Song
+
+	err = db.Export(context.Background(), filters, func(song models.Song) error {
+		songs = append(songs, song)
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("error not expected while exporting: %s", err)
+	}
+
+	if len(songs) != 1 || songs[0] != (models.Song{Id: 1, Song: "TestSong"}) {
+		t.Fatalf("error: want only selected fields, but got %+v", songs)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCount(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectQuery("^SELECT count(.+) FROM songs$").
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(3))
+
+	db := NewStorage(mock)
+
+	count, err := db.Count(context.Background())
+	if err != nil || count != 3 {
+		t.Fatalf("error: want 3 songs, but got %v, %v", count, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %s", err)
+	}
+}
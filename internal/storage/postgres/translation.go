@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+	"github.com/s3nn1k/ef-mob-task/pkg/logger"
+)
+
+// SetTranslation creates or replaces translation of song
+// Returns false if song not exists
+func (s *Storage) SetTranslation(ctx context.Context, translation models.Translation) (bool, error) {
+	logger.LogUse(ctx).Debug("Storage.Postgres.SetTranslation", "input", translation.LogValue())
+
+	query := fmt.Sprintf("INSERT INTO %s (song_id, lang, text) VALUES (@id, @lang, @text) "+
+		"ON CONFLICT (song_id, lang) DO UPDATE SET text=EXCLUDED.text", transTable)
+
+	args := pgx.NamedArgs{
+		"id":   translation.Id,
+		"lang": translation.Lang,
+		"text": translation.Text,
+	}
+
+	if _, err := s.db.Exec(ctx, query, args); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+			logger.LogUse(ctx).Debug("Result", slog.Bool("set", false))
+
+			return false, nil
+		}
+
+		return false, fmt.Errorf("can't set translation in storage: %w", err)
+	}
+
+	logger.LogUse(ctx).Debug("Result", slog.Bool("set", true))
+
+	return true, nil
+}
+
+func (s *Storage) GetTranslation(ctx context.Context, id int, lang string) (models.Translation, bool, error) {
+	logger.LogUse(ctx).Debug("Storage.Postgres.GetTranslation", slog.Int("id", id), slog.String("lang", lang))
+
+	query := fmt.Sprintf("SELECT song_id, lang, text FROM %s WHERE song_id=@id AND lang=@lang", transTable)
+
+	args := pgx.NamedArgs{
+		"id":   id,
+		"lang": lang,
+	}
+
+	var translation models.Translation
+
+	err := s.db.QueryRow(ctx, query, args).Scan(&translation.Id, &translation.Lang, &translation.Text)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.LogUse(ctx).Debug("Result", slog.Bool("found", false))
+
+			return models.Translation{}, false, nil
+		}
+
+		return models.Translation{}, false, fmt.Errorf("can't get translation from storage: %w", err)
+	}
+
+	logger.LogUse(ctx).Debug("Result", "translation", translation.LogValue())
+
+	return translation, true, nil
+}
+
+// GetTranslationLangs returns sorted languages of all song translations
+func (s *Storage) GetTranslationLangs(ctx context.Context, id int) ([]string, error) {
+	logger.LogUse(ctx).Debug("Storage.Postgres.GetTranslationLangs", "input", slog.Int("id", id))
+
+	query := fmt.Sprintf("SELECT lang FROM %s WHERE song_id=@id ORDER BY lang", transTable)
+
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+
+	rows, err := s.db.Query(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("can't get translations from storage: %w", err)
+	}
+	defer rows.Close()
+
+	langs := []string{}
+
+	for rows.Next() {
+		var lang string
+
+		if err := rows.Scan(&lang); err != nil {
+			return nil, fmt.Errorf("can't get translations from storage: %w", err)
+		}
+
+		langs = append(langs, lang)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("can't get translations from storage: %w", err)
+	}
+
+	logger.LogUse(ctx).Debug("Result", slog.Any("langs", langs))
+
+	return langs, nil
+}
+
+func (s *Storage) DeleteTranslation(ctx context.Context, id int, lang string) (bool, error) {
+	logger.LogUse(ctx).Debug("Storage.Postgres.DeleteTranslation", slog.Int("id", id), slog.String("lang", lang))
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE song_id=@id AND lang=@lang", transTable)
+
+	args := pgx.NamedArgs{
+		"id":   id,
+		"lang": lang,
+	}
+
+	rows, err := s.db.Exec(ctx, query, args)
+	if err != nil {
+		return false, fmt.Errorf("can't delete translation from storage: %w", err)
+	}
+
+	res := true
+	if rows.RowsAffected() == 0 {
+		res = false
+	}
+
+	logger.LogUse(ctx).Debug("Result", slog.Bool("deleted", res))
+
+	return res, nil
+}
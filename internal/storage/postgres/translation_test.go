@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/s3nn1k/ef-mob-task/internal/models"
+)
+
+func TestSetTranslation(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	translation := models.Translation{
+		Id:   1,
+		Lang: "en",
+		Text: "TestText",
+	}
+
+	mock.ExpectExec("^INSERT INTO song_translations (.+) ON CONFLICT (.+)$").
+		WithArgs(translation.Id, translation.Lang, translation.Text).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	mock.ExpectExec("^INSERT INTO song_translations (.+) ON CONFLICT (.+)$").
+		WithArgs(translation.Id, translation.Lang, translation.Text).
+		WillReturnError(&pgconn.PgError{Code: foreignKeyViolation})
+
+	db := NewStorage(mock)
+
+	ok, err := db.SetTranslation(context.Background(), translation)
+	if err != nil {
+		t.Fatalf("error not expected while setting translation: %s", err)
+	}
+
+	if !ok {
+		t.Fatal("error: result of setting translation must be true")
+	}
+
+	ok, err = db.SetTranslation(context.Background(), translation)
+	if err != nil {
+		t.Fatalf("error not expected while setting translation of not existing song: %s", err)
+	}
+
+	if ok {
+		t.Fatal("error: result of setting translation of not existing song must be false")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGetTranslationLangs(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := 1
+
+	mock.ExpectQuery("^SELECT lang FROM song_translations WHERE (.+) ORDER BY lang$").
+		WithArgs(id).
+		WillReturnRows(pgxmock.NewRows([]string{"lang"}).AddRow("de").AddRow("en"))
+
+	db := NewStorage(mock)
+
+	langs, err := db.GetTranslationLangs(context.Background(), id)
+	if err != nil {
+		t.Fatalf("error not expected while getting translations: %s", err)
+	}
+
+	if len(langs) != 2 || langs[0] != "de" || langs[1] != "en" {
+		t.Fatalf("error: must get same languages as in storage, but got %v", langs)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -9,7 +9,35 @@ import (
 // go run github.com/vektra/mockery/v2@v2.45.0 --name=Storage
 type Storage interface {
 	Create(ctx context.Context, song models.Song) (int, error)
+	CreateBatch(ctx context.Context, songs []models.Song) ([]int, error)
 	Update(ctx context.Context, song models.Song) (bool, error)
 	GetAll(ctx context.Context, filters models.GetFilters) ([]models.Song, error)
+	Export(ctx context.Context, filters models.GetFilters, fn func(models.Song) error) error
+	Count(ctx context.Context) (int, error)
 	Delete(ctx context.Context, id int) (bool, error)
+	DeleteBatch(ctx context.Context, filters models.DeleteFilters) ([]int, error)
+	SetLyrics(ctx context.Context, id int, lines []models.LyricLine) (bool, error)
+	GetLyrics(ctx context.Context, id int) ([]models.LyricLine, error)
+	SetTranslation(ctx context.Context, translation models.Translation) (bool, error)
+	GetTranslation(ctx context.Context, id int, lang string) (models.Translation, bool, error)
+	GetTranslationLangs(ctx context.Context, id int) ([]string, error)
+	DeleteTranslation(ctx context.Context, id int, lang string) (bool, error)
+}
+
+// go run github.com/vektra/mockery/v2@v2.45.0 --name=PlaylistStorage
+type PlaylistStorage interface {
+	Create(ctx context.Context, playlist models.Playlist) (int, error)
+	Get(ctx context.Context, filters models.GetPlaylistFilters) (models.Playlist, bool, error)
+	Delete(ctx context.Context, id int) (bool, error)
+	AddSong(ctx context.Context, item models.PlaylistItem) (bool, error)
+	MoveSong(ctx context.Context, move models.PlaylistMove) (bool, error)
+	RemoveSong(ctx context.Context, item models.PlaylistItem) (bool, error)
+}
+
+// go run github.com/vektra/mockery/v2@v2.45.0 --name=KeyStorage
+type KeyStorage interface {
+	Create(ctx context.Context, key models.APIKey, hash string) (models.APIKey, error)
+	GetByHash(ctx context.Context, hash string) (models.APIKey, bool, error)
+	List(ctx context.Context) ([]models.APIKey, error)
+	Revoke(ctx context.Context, id int) (bool, error)
 }
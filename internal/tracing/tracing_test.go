@@ -0,0 +1,25 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/s3nn1k/ef-mob-task/internal/config"
+)
+
+func TestSetup(t *testing.T) {
+	for _, exporter := range []string{config.ExporterNone, config.ExporterStdout} {
+		shutdown, err := Setup(context.Background(), config.Tracing{Exporter: exporter, ServiceName: "test", SampleRatio: 1})
+		if err != nil {
+			t.Fatalf("error not expected while setup %q exporter: %s", exporter, err)
+		}
+
+		if err := shutdown(context.Background()); err != nil {
+			t.Fatalf("error not expected while shutdown %q exporter: %s", exporter, err)
+		}
+	}
+
+	if _, err := Setup(context.Background(), config.Tracing{Exporter: "zipkin"}); err == nil {
+		t.Fatal("error expected for unknown exporter")
+	}
+}
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS ix_playlist_items_song;
+DROP TABLE IF EXISTS playlist_items;
+DROP TABLE IF EXISTS playlists;
\ No newline at end of file
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS playlists (
+    id serial primary key unique not null,
+    name varchar(255) not null
+);
+
+CREATE TABLE IF NOT EXISTS playlist_items (
+    id serial primary key unique not null,
+    playlist_id int not null references playlists(id) on delete cascade,
+    song_id int not null references songs(id) on delete cascade,
+    position int not null,
+    unique (playlist_id, position) deferrable initially deferred
+);
+
+CREATE INDEX ix_playlist_items_song ON playlist_items(song_id);
\ No newline at end of file
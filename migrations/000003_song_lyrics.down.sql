@@ -0,0 +1 @@
+DROP TABLE IF EXISTS song_lyrics;
\ No newline at end of file
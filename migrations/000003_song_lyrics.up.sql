@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS song_lyrics (
+    song_id int not null references songs(id) on delete cascade,
+    line int not null,
+    time_ms bigint not null,
+    text text not null,
+    primary key (song_id, line)
+);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS song_translations;
\ No newline at end of file
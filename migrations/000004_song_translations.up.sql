@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS song_translations (
+    song_id int not null references songs(id) on delete cascade,
+    lang varchar(35) not null,
+    text text not null,
+    primary key (song_id, lang)
+);
\ No newline at end of file
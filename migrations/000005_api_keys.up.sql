@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+    id serial primary key unique not null,
+    name varchar(255) not null,
+    prefix varchar(16) not null,
+    hash char(64) unique not null,
+    scope varchar(16) not null,
+    created_at timestamptz not null default now(),
+    revoked_at timestamptz
+);
\ No newline at end of file
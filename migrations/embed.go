@@ -0,0 +1,9 @@
+// Package migrations embeds sql migrations of database schema into binary
+package migrations
+
+import "embed"
+
+// FS contains up and down migrations named as version_title.up.sql and version_title.down.sql
+//
+//go:embed *.sql
+var FS embed.FS
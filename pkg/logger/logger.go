@@ -2,28 +2,91 @@ package logger
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
+
+	"go.opentelemetry.io/otel/trace"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 // Available logging levels
 const (
-	levelInfo = "info"
-	levelWarn = "warning"
-	levelErr  = "error"
+	levelDebug = "debug"
+	levelInfo  = "info"
+	levelWarn  = "warning"
+	levelErr   = "error"
 )
 
-// Create new text logger with level. Debug by default
-func NewTextLogger(level string) *slog.Logger {
+// Available output formats
+const (
+	FormatText = "text"
+	FormatJson = "json"
+)
+
+// ParseLevel returns slog level by it's name. Empty name means debug level
+func ParseLevel(level string) (slog.Level, error) {
 	switch level {
+	case "", levelDebug:
+		return slog.LevelDebug, nil
 	case levelInfo:
-		return slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+		return slog.LevelInfo, nil
 	case levelWarn:
-		return slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelWarn}))
+		return slog.LevelWarn, nil
 	case levelErr:
-		return slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("unknown log level %q, must be debug, info, warning or error", level)
+	}
+}
+
+// LevelName returns name of slog level, that is accepted by ParseLevel
+func LevelName(level slog.Level) string {
+	switch {
+	case level < slog.LevelInfo:
+		return levelDebug
+	case level < slog.LevelWarn:
+		return levelInfo
+	case level < slog.LevelError:
+		return levelWarn
 	default:
-		return slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		return levelErr
+	}
+}
+
+// IsFormat checks that format is available
+func IsFormat(format string) bool {
+	return format == "" || format == FormatText || format == FormatJson
+}
+
+// Create new text logger with level. Debug by default
+func NewTextLogger(level string) *slog.Logger {
+	lvl, _ := ParseLevel(level)
+
+	return slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: lvl}))
+}
+
+// New creates logger that writes to out in the given format, text by default
+// Level could be changed at runtime through level var
+func New(out io.Writer, format string, level *slog.LevelVar) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+
+	if format == FormatJson {
+		return slog.New(slog.NewJSONHandler(out, opts))
+	}
+
+	return slog.New(slog.NewTextHandler(out, opts))
+}
+
+// NewFileWriter creates writer to file, that is rotated when it reaches maxSize megabytes
+// Rotated files are removed when there are more than maxBackups of them or they are older than maxAge days
+func NewFileWriter(path string, maxSize int, maxBackups int, maxAge int) io.WriteCloser {
+	return &lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    maxSize,
+		MaxBackups: maxBackups,
+		MaxAge:     maxAge,
 	}
 }
 
@@ -32,12 +95,40 @@ func NewCtxWithLog(ctx context.Context, log *slog.Logger) context.Context {
 	return context.WithValue(ctx, slog.Logger{}, log)
 }
 
+// type requestIdKey is the context key of request id
+type requestIdKey struct{}
+
+// Set request id into context
+func NewCtxWithRequestId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIdKey{}, id)
+}
+
+// RequestId returns request id from context or empty string if it's not set
+func RequestId(ctx context.Context) string {
+	id, _ := ctx.Value(requestIdKey{}).(string)
+	return id
+}
+
 // Use context logger value to log data
-// Returns logger with debug level by default
+// Returns logger with debug level by default. Request id and ids of trace and span are added if context has them
 func LogUse(ctx context.Context) *slog.Logger {
-	if log, ok := ctx.Value(slog.Logger{}).(*slog.Logger); ok {
-		return log
+	log, ok := ctx.Value(slog.Logger{}).(*slog.Logger)
+	if !ok {
+		log = NewTextLogger("")
+	}
+
+	return LogWith(ctx, log)
+}
+
+// LogWith returns the given logger with request id and ids of trace and span if context has them
+func LogWith(ctx context.Context, log *slog.Logger) *slog.Logger {
+	if id := RequestId(ctx); id != "" {
+		log = log.With(slog.String("requestId", id))
+	}
+
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		log = log.With(slog.String("traceId", sc.TraceID().String()), slog.String("spanId", sc.SpanID().String()))
 	}
 
-	return NewTextLogger("")
+	return log
 }
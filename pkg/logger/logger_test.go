@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   string
+		want    slog.Level
+		wantErr bool
+	}{
+		{name: "empty", level: "", want: slog.LevelDebug},
+		{name: "info", level: "info", want: slog.LevelInfo},
+		{name: "warning", level: "warning", want: slog.LevelWarn},
+		{name: "error", level: "error", want: slog.LevelError},
+		{name: "unknown", level: "verbose", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLevel(tt.level)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error: want error %t, but got %v", tt.wantErr, err)
+			}
+
+			if !tt.wantErr && got != tt.want {
+				t.Fatalf("error: want level %s, but got %s", tt.want, got)
+			}
+
+			if !tt.wantErr && tt.level != "" && LevelName(got) != tt.level {
+				t.Fatalf("error: want name %s, but got %s", tt.level, LevelName(got))
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelWarn)
+
+	log := New(&buf, FormatJson, level)
+
+	log.Info("Skipped")
+
+	if buf.Len() != 0 {
+		t.Fatalf("error: info must be skipped on warning level, but got %s", buf.String())
+	}
+
+	level.Set(slog.LevelInfo)
+
+	log.Info("Written", slog.Int("id", 1))
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("error: want json entry, but got %s", buf.String())
+	}
+
+	if entry["msg"] != "Written" || entry["id"] != float64(1) {
+		t.Fatalf("error: unexpected entry %v", entry)
+	}
+}
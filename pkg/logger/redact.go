@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// hidden replaces values that must never be logged
+const hidden = "[hidden]"
+
+// type Policy represents how large and sensitive values are logged
+// Zero values of limits mean that values are logged as is
+type Policy struct {
+	// MaxFieldLen is the maximum count of characters of logged string
+	MaxFieldLen int
+	// MaxItems is the maximum count of logged items of list
+	MaxItems int
+	// RedactText replaces lyrics and other licensed content with it's size
+	RedactText bool
+}
+
+var policy atomic.Pointer[Policy]
+
+func init() {
+	policy.Store(&Policy{})
+}
+
+// SetPolicy sets policy that is used by LogValue methods of all types
+func SetPolicy(p Policy) {
+	policy.Store(&p)
+}
+
+// CurrentPolicy returns policy in use
+func CurrentPolicy() Policy {
+	return *policy.Load()
+}
+
+// Truncate cuts string to the maximum field length of policy and appends count of cut characters
+func Truncate(s string) string {
+	max := policy.Load().MaxFieldLen
+	if max <= 0 || utf8.RuneCountInString(s) <= max {
+		return s
+	}
+
+	runes := []rune(s)
+
+	return fmt.Sprintf("%s...(%d more)", string(runes[:max]), len(runes)-max)
+}
+
+// Text returns value of licensed content, that is truncated or redacted according to policy
+func Text(key string, s string) slog.Attr {
+	if policy.Load().RedactText && s != "" {
+		return slog.String(key, fmt.Sprintf("[redacted %d chars]", utf8.RuneCountInString(s)))
+	}
+
+	return slog.String(key, Truncate(s))
+}
+
+// String returns attribute with value truncated according to policy
+func String(key string, s string) slog.Attr {
+	return slog.String(key, Truncate(s))
+}
+
+// Secret returns attribute that only shows whether secret is set
+func Secret(key string, s string) slog.Attr {
+	if s == "" {
+		return slog.String(key, "")
+	}
+
+	return slog.String(key, hidden)
+}
+
+// type list logs items lazily, only when record is handled
+type list[T any] struct {
+	items []T
+	value func(item *T) slog.Value
+}
+
+// List returns slog.LogValuer of items, that logs no more items than policy allows and the total count
+func List[T any, P interface {
+	*T
+	slog.LogValuer
+}](items []T) slog.LogValuer {
+	return list[T]{items: items, value: func(item *T) slog.Value { return P(item).LogValue() }}
+}
+
+// ListFunc returns slog.LogValuer of items like List, for items without LogValue method
+func ListFunc[T any](items []T, value func(item T) slog.Value) slog.LogValuer {
+	return list[T]{items: items, value: func(item *T) slog.Value { return value(*item) }}
+}
+
+func (l list[T]) LogValue() slog.Value {
+	logged := len(l.items)
+
+	if max := policy.Load().MaxItems; max > 0 && logged > max {
+		logged = max
+	}
+
+	values := make([]slog.Value, 0, logged)
+	for i := range l.items[:logged] {
+		values = append(values, l.value(&l.items[i]))
+	}
+
+	return slog.GroupValue(
+		slog.Int("count", len(l.items)),
+		slog.Any("items", values),
+	)
+}
@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type item struct {
+	name string
+}
+
+func (i *item) LogValue() slog.Value {
+	return slog.StringValue(i.name)
+}
+
+func TestPolicy(t *testing.T) {
+	defer SetPolicy(Policy{})
+
+	SetPolicy(Policy{MaxFieldLen: 5, MaxItems: 2})
+
+	if got := Truncate("Привет, мир"); got != "Приве...(6 more)" {
+		t.Fatalf("error: string must be cut by characters, but got %q", got)
+	}
+
+	if got := Truncate("short"); got != "short" {
+		t.Fatalf("error: short string must not be cut, but got %q", got)
+	}
+
+	if got := Text("text", "Verse one").Value.String(); got != "Verse...(4 more)" {
+		t.Fatalf("error: text must be truncated, but got %q", got)
+	}
+
+	SetPolicy(Policy{RedactText: true})
+
+	if got := Text("text", "Verse one").Value.String(); got != "[redacted 9 chars]" {
+		t.Fatalf("error: text must be redacted, but got %q", got)
+	}
+
+	if got := Secret("pass", "postgres").Value.String(); got != hidden {
+		t.Fatalf("error: secret must be hidden, but got %q", got)
+	}
+}
+
+func TestList(t *testing.T) {
+	defer SetPolicy(Policy{})
+
+	SetPolicy(Policy{MaxItems: 2})
+
+	items := []item{{name: "first"}, {name: "second"}, {name: "third"}}
+
+	got := List(items).LogValue().Resolve().String()
+
+	if !strings.Contains(got, "count=3") || !strings.Contains(got, "second") || strings.Contains(got, "third") {
+		t.Fatalf("error: want total count and only two items, but got %s", got)
+	}
+
+	got = ListFunc([]string{"first", "second", "third"}, slog.StringValue).LogValue().Resolve().String()
+
+	if !strings.Contains(got, "count=3") || !strings.Contains(got, "second") || strings.Contains(got, "third") {
+		t.Fatalf("error: want total count and only two items of func, but got %s", got)
+	}
+}
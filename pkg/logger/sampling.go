@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// sampleTick is the period after which counters of messages are reset
+const sampleTick = time.Second
+
+// type sampleKey identifies records by message and place where they are logged
+// Generic messages like "Result" are logged from many places and must not be sampled together
+type sampleKey struct {
+	msg string
+	pc  uintptr
+}
+
+// type sampler counts debug records with the same message and source during tick
+type sampler struct {
+	mu         sync.Mutex
+	first      int
+	thereafter int
+	reset      time.Time
+	counts     map[sampleKey]int
+	now        func() time.Time
+}
+
+// allow checks that record with the given message and program counter should be handled
+func (s *sampler) allow(msg string, pc uintptr) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now := s.now(); now.Sub(s.reset) >= sampleTick {
+		s.reset = now
+		clear(s.counts)
+	}
+
+	key := sampleKey{msg: msg, pc: pc}
+
+	s.counts[key]++
+	n := s.counts[key]
+
+	if n <= s.first {
+		return true
+	}
+
+	return s.thereafter > 0 && (n-s.first)%s.thereafter == 0
+}
+
+// type samplingHandler drops debug records with the same message and source after first of them during every second
+// Records of info and higher levels are never dropped
+type samplingHandler struct {
+	slog.Handler
+	sampler *sampler
+}
+
+// NewSamplingHandler wraps handler with sampling of debug records
+// First records with the same message from the same place are handled every second and then only every thereafter record
+func NewSamplingHandler(h slog.Handler, first int, thereafter int) slog.Handler {
+	return &samplingHandler{
+		Handler: h,
+		sampler: &sampler{
+			first:      first,
+			thereafter: thereafter,
+			counts:     make(map[sampleKey]int),
+			now:        time.Now,
+		},
+	}
+}
+
+func (h *samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < slog.LevelInfo && !h.sampler.allow(r.Message, r.PC) {
+		return nil
+	}
+
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{Handler: h.Handler.WithAttrs(attrs), sampler: h.sampler}
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{Handler: h.Handler.WithGroup(name), sampler: h.sampler}
+}
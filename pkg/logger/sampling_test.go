@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSamplingHandler(t *testing.T) {
+	var buf bytes.Buffer
+
+	h := NewSamplingHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), 2, 3).(*samplingHandler)
+
+	now := time.Now()
+	h.sampler.now = func() time.Time { return now }
+
+	log := slog.New(h).With(slog.String("requestId", "TestRequestId"))
+
+	for range 8 {
+		log.Debug("Result")
+		log.Info("Request")
+	}
+
+	// 2 first records and then every third of 6 remaining
+	if got := strings.Count(buf.String(), "msg=Result"); got != 4 {
+		t.Fatalf("error: want 4 sampled debug records, but got %d", got)
+	}
+
+	if got := strings.Count(buf.String(), "msg=Request"); got != 8 {
+		t.Fatalf("error: info records must not be sampled, but got %d", got)
+	}
+
+	// Same message from other place is counted separately
+	buf.Reset()
+
+	log.Debug("Result")
+
+	if got := strings.Count(buf.String(), "msg=Result"); got != 1 {
+		t.Fatal("error: records from different places must be counted separately")
+	}
+
+	now = now.Add(sampleTick)
+	buf.Reset()
+
+	log.Debug("Result")
+
+	if got := strings.Count(buf.String(), "msg=Result"); got != 1 {
+		t.Fatal("error: counters must be reset after tick")
+	}
+}
@@ -0,0 +1,178 @@
+package lrc
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Available metadata tags
+const (
+	TagTitle  = "ti"
+	TagArtist = "ar"
+	TagAlbum  = "al"
+	TagOffset = "offset"
+)
+
+var (
+	// timestamp matches time tags like [01:02.03], [01:02:03] or [01:02]
+	timestamp = regexp.MustCompile(`^\[(\d{1,3}):(\d{2})(?:[.:](\d{1,3}))?\]`)
+	// metadata matches id tags like [ar:Artist]
+	metadata = regexp.MustCompile(`^\[([a-zA-Z#]+):(.*)\]$`)
+)
+
+// type Line represents line of lyrics with it's start time
+type Line struct {
+	Time time.Duration
+	Text string
+}
+
+// type Lyrics represents parsed lrc file
+type Lyrics struct {
+	Tags  map[string]string
+	Lines []Line
+}
+
+// Parse reads lrc file and returns lines sorted by time
+// Lines with several time tags are duplicated for every tag, offset tag is applied to all lines
+func Parse(r io.Reader) (Lyrics, error) {
+	lyrics := Lyrics{Tags: make(map[string]string)}
+
+	scanner := bufio.NewScanner(r)
+
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var times []time.Duration
+
+		for {
+			m := timestamp.FindStringSubmatch(line)
+			if m == nil {
+				break
+			}
+
+			t, err := parseTime(m[1], m[2], m[3])
+			if err != nil {
+				return Lyrics{}, fmt.Errorf("line %d: %w", n, err)
+			}
+
+			times = append(times, t)
+			line = line[len(m[0]):]
+		}
+
+		if len(times) == 0 {
+			m := metadata.FindStringSubmatch(line)
+			if m == nil {
+				return Lyrics{}, fmt.Errorf("line %d: invalid syntax, time or metadata tag expected", n)
+			}
+
+			lyrics.Tags[strings.ToLower(m[1])] = strings.TrimSpace(m[2])
+
+			continue
+		}
+
+		for _, t := range times {
+			lyrics.Lines = append(lyrics.Lines, Line{Time: t, Text: strings.TrimSpace(line)})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return Lyrics{}, err
+	}
+
+	if len(lyrics.Lines) == 0 {
+		return Lyrics{}, fmt.Errorf("no timed lines found")
+	}
+
+	if val, ok := lyrics.Tags[TagOffset]; ok {
+		offset, err := strconv.Atoi(strings.TrimPrefix(val, "+"))
+		if err != nil {
+			return Lyrics{}, fmt.Errorf("offset must be int milliseconds: %s", val)
+		}
+
+		// Positive offset means that lyrics appear sooner
+		for i := range lyrics.Lines {
+			lyrics.Lines[i].Time -= time.Duration(offset) * time.Millisecond
+
+			if lyrics.Lines[i].Time < 0 {
+				return Lyrics{}, fmt.Errorf("offset %dms moves line %q before the start of song", offset, lyrics.Lines[i].Text)
+			}
+		}
+
+		delete(lyrics.Tags, TagOffset)
+	}
+
+	slices.SortStableFunc(lyrics.Lines, func(a, b Line) int {
+		return cmp.Compare(a.Time, b.Time)
+	})
+
+	return lyrics, nil
+}
+
+// Write writes lyrics in lrc format, tags are written in sorted order before lines
+func Write(w io.Writer, lyrics Lyrics) error {
+	keys := make([]string, 0, len(lyrics.Tags))
+	for key := range lyrics.Tags {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		if _, err := fmt.Fprintf(w, "[%s:%s]\n", key, lyrics.Tags[key]); err != nil {
+			return err
+		}
+	}
+
+	for _, line := range lyrics.Lines {
+		if _, err := fmt.Fprintf(w, "%s%s\n", FormatTime(line.Time), line.Text); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FormatTime returns time tag in [mm:ss.xx] format
+func FormatTime(t time.Duration) string {
+	centis := t.Milliseconds() / 10
+
+	return fmt.Sprintf("[%02d:%02d.%02d]", centis/6000, centis/100%60, centis%100)
+}
+
+// parseTime converts parts of time tag into time.Duration
+// Fraction could be hundredths or milliseconds
+func parseTime(min, sec, frac string) (time.Duration, error) {
+	m, _ := strconv.Atoi(min)
+	s, _ := strconv.Atoi(sec)
+
+	if s > 59 {
+		return 0, fmt.Errorf("invalid time tag %s:%s, seconds must be less than 60", min, sec)
+	}
+
+	t := time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+
+	if frac != "" {
+		f, _ := strconv.Atoi(frac)
+
+		switch len(frac) {
+		case 1:
+			t += time.Duration(f) * 100 * time.Millisecond
+		case 2:
+			t += time.Duration(f) * 10 * time.Millisecond
+		default:
+			t += time.Duration(f) * time.Millisecond
+		}
+	}
+
+	return t, nil
+}
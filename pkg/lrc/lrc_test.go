@@ -0,0 +1,125 @@
+package lrc
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		wantLines []Line
+		wantErr   bool
+	}{
+		{
+			name: "simple",
+			file: "[ti:Song]\n[ar:Group]\n\n[00:01.50]first\n[00:03.25] second \n[01:00.100]third",
+			wantLines: []Line{
+				{Time: 1500 * time.Millisecond, Text: "first"},
+				{Time: 3250 * time.Millisecond, Text: "second"},
+				{Time: time.Minute + 100*time.Millisecond, Text: "third"},
+			},
+		},
+		{
+			name: "repeated and unordered",
+			file: "[00:10.00][00:02.00]chorus\r\n[00:05.00]verse",
+			wantLines: []Line{
+				{Time: 2 * time.Second, Text: "chorus"},
+				{Time: 5 * time.Second, Text: "verse"},
+				{Time: 10 * time.Second, Text: "chorus"},
+			},
+		},
+		{
+			name: "offset",
+			file: "[offset:+500]\n[00:01.00]first\n[00:02.00]",
+			wantLines: []Line{
+				{Time: 500 * time.Millisecond, Text: "first"},
+				{Time: 1500 * time.Millisecond, Text: ""},
+			},
+		},
+		{
+			name:    "invalid syntax",
+			file:    "[00:01.00]first\nsecond",
+			wantErr: true,
+		},
+		{
+			name:    "invalid seconds",
+			file:    "[00:61.00]first",
+			wantErr: true,
+		},
+		{
+			name:    "invalid offset",
+			file:    "[offset:soon]\n[00:01.00]first",
+			wantErr: true,
+		},
+		{
+			name:    "negative time after offset",
+			file:    "[offset:2000]\n[00:01.00]first",
+			wantErr: true,
+		},
+		{
+			name:    "no lines",
+			file:    "[ti:Song]",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lyrics, err := Parse(strings.NewReader(test.file))
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("error expected while parsing invalid file")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("error not expected while parsing: %s", err)
+			}
+
+			if len(lyrics.Lines) != len(test.wantLines) {
+				t.Fatalf("error: want %v lines, but got %v", len(test.wantLines), len(lyrics.Lines))
+			}
+
+			for i := range lyrics.Lines {
+				if lyrics.Lines[i] != test.wantLines[i] {
+					t.Fatalf("error: want %+v line with %v index, but got %+v", test.wantLines[i], i, lyrics.Lines[i])
+				}
+			}
+
+			if _, ok := lyrics.Tags[TagOffset]; ok {
+				t.Fatal("error: offset tag must be applied and removed")
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	lyrics := Lyrics{
+		Tags: map[string]string{
+			TagTitle:  "Song",
+			TagArtist: "Group",
+		},
+		Lines: []Line{
+			{Time: 1500 * time.Millisecond, Text: "first"},
+			{Time: 61*time.Second + 250*time.Millisecond, Text: "second"},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	if err := Write(&buf, lyrics); err != nil {
+		t.Fatalf("error not expected while writing: %s", err)
+	}
+
+	want := "[ar:Group]\n[ti:Song]\n[00:01.50]first\n[01:01.25]second\n"
+
+	if buf.String() != want {
+		t.Fatalf("error: want %q, but got %q", want, buf.String())
+	}
+}
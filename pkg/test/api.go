@@ -12,6 +12,7 @@ type TestCase struct {
 	Url        string
 	Method     string
 	Body       string
+	Headers    map[string]string
 	WantStatus int
 	WantRes    string
 }
@@ -23,6 +24,10 @@ func TestEndpoint(t *testing.T, handler http.Handler, test TestCase) {
 			t.Fatalf("error not expected while creating request: %s", err)
 		}
 
+		for key, val := range test.Headers {
+			req.Header.Set(key, val)
+		}
+
 		res := httptest.NewRecorder()
 
 		handler.ServeHTTP(res, req)